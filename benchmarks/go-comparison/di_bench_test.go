@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/goioc/di"
+	rustdi "github.com/pegasusheavy/dependency-injector/ffi/go/di"
 	"github.com/samber/do/v2"
 	"go.uber.org/dig"
 )
@@ -220,6 +221,19 @@ func BenchmarkSingletonResolution(b *testing.B) {
 			_ = di.GetInstance("config")
 		}
 	})
+
+	// Rust FFI backend, via the generic Provide/Resolve layer
+	b.Run("rust_ffi_generic", func(b *testing.B) {
+		container := rustdi.NewContainer()
+		defer container.Free()
+		rustdi.Provide(container, func(c *rustdi.Container) (*Config, error) {
+			return NewConfig(), nil
+		})
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = rustdi.Resolve[*Config](container)
+		}
+	})
 }
 
 // =============================================================================
@@ -308,6 +322,31 @@ func BenchmarkDeepDependencyChain(b *testing.B) {
 			_, _ = do.Invoke[*UserService](injector)
 		}
 	})
+
+	// Rust FFI backend, via the generic Provide/Resolve layer
+	b.Run("rust_ffi_generic", func(b *testing.B) {
+		container := rustdi.NewContainer()
+		defer container.Free()
+		rustdi.Provide(container, func(c *rustdi.Container) (*Config, error) {
+			return NewConfig(), nil
+		})
+		rustdi.Provide(container, func(c *rustdi.Container) (*Database, error) {
+			config, _ := rustdi.Resolve[*Config](c)
+			return NewDatabase(config), nil
+		})
+		rustdi.Provide(container, func(c *rustdi.Container) (*UserRepository, error) {
+			db, _ := rustdi.Resolve[*Database](c)
+			return NewUserRepository(db), nil
+		})
+		rustdi.Provide(container, func(c *rustdi.Container) (*UserService, error) {
+			repo, _ := rustdi.Resolve[*UserRepository](c)
+			return NewUserService(repo), nil
+		})
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = rustdi.Resolve[*UserService](container)
+		}
+	})
 }
 
 // =============================================================================
@@ -0,0 +1,217 @@
+package di
+
+/*
+#include "dependency_injector.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Registration is one entry in a RegisterBatch call.
+type Registration struct {
+	Name string
+	Data []byte
+}
+
+// MultiError aggregates one error per failed name from a batch operation
+// (ResolveAll, ResolveAllInto) instead of stopping at the first.
+type MultiError struct {
+	Errors map[string]error
+}
+
+func (e *MultiError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for name, err := range e.Errors {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, err))
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("resolve: %d error(s): %s", len(e.Errors), strings.Join(parts, "; "))
+}
+
+// ResolveAll resolves every name in names, crossing the FFI boundary once
+// for every name that isn't backed by a Go-side factory (via a single
+// batched di_container_resolve_many call) and fanning the rest out over a
+// worker pool sized to GOMAXPROCS. It returns everything it could resolve
+// alongside a *MultiError for anything it couldn't, so a handful of
+// missing names doesn't discard the rest of the batch.
+func (c *Container) ResolveAll(names []string) (map[string][]byte, error) {
+	if c.ptr == nil {
+		return nil, &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+	results := make(map[string][]byte, len(names))
+	if len(names) == 0 {
+		return results, nil
+	}
+
+	var ffiNames, factoryNames []string
+	for _, name := range names {
+		if reg, _ := c.lookupFactory(name); reg != nil {
+			factoryNames = append(factoryNames, name)
+		} else {
+			ffiNames = append(ffiNames, name)
+		}
+	}
+
+	me := &MultiError{Errors: make(map[string]error)}
+
+	if len(ffiNames) > 0 {
+		data, errs := c.resolveManyFFI(ffiNames)
+		for name, d := range data {
+			results[name] = d
+		}
+		for name, err := range errs {
+			me.Errors[name] = err
+		}
+	}
+
+	if len(factoryNames) > 0 {
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+
+		for _, name := range factoryNames {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(name string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				data, err := c.Resolve(name)
+
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					me.Errors[name] = err
+				} else {
+					results[name] = data
+				}
+			}(name)
+		}
+		wg.Wait()
+	}
+
+	if len(me.Errors) > 0 {
+		return results, me
+	}
+	return results, nil
+}
+
+// ResolveAllInto resolves every key of targets and unmarshals its JSON into
+// the corresponding value, which must be a pointer. Like ResolveAll, it
+// collects every failure into a *MultiError rather than stopping early.
+func (c *Container) ResolveAllInto(targets map[string]any) error {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+
+	data, resolveErr := c.ResolveAll(names)
+	me, _ := resolveErr.(*MultiError)
+	if resolveErr != nil && me == nil {
+		return resolveErr
+	}
+	if me == nil {
+		me = &MultiError{Errors: make(map[string]error)}
+	}
+
+	for name, target := range targets {
+		d, ok := data[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(d, target); err != nil {
+			me.Errors[name] = err
+		}
+	}
+
+	if len(me.Errors) > 0 {
+		return me
+	}
+	return nil
+}
+
+// resolveManyFFI resolves names directly against the Rust container in a
+// single di_container_resolve_many call, returning per-name data and
+// per-name errors.
+func (c *Container) resolveManyFFI(names []string) (map[string][]byte, map[string]error) {
+	data := make(map[string][]byte, len(names))
+	errs := make(map[string]error)
+
+	cNames := make([]*C.char, len(names))
+	for i, name := range names {
+		cNames[i] = C.CString(name)
+	}
+	defer func() {
+		for _, cName := range cNames {
+			C.free(unsafe.Pointer(cName))
+		}
+	}()
+
+	clearError()
+	result := C.di_container_resolve_many(c.ptr, &cNames[0], C.size_t(len(cNames)))
+	if result == nil {
+		msg := getLastError()
+		for _, name := range names {
+			errs[name] = &DIError{Code: InternalError, Message: msg}
+		}
+		return data, errs
+	}
+	defer C.di_resolve_many_free(result)
+
+	count := int(C.di_resolve_many_count(result))
+	for i := 0; i < count; i++ {
+		name := C.GoString(C.di_resolve_many_name(result, C.size_t(i)))
+		if errPtr := C.di_resolve_many_error(result, C.size_t(i)); errPtr != nil {
+			errs[name] = &DIError{Code: NotFound, Message: C.GoString(errPtr)}
+			continue
+		}
+		data[name] = []byte(C.GoString(C.di_resolve_many_data(result, C.size_t(i))))
+	}
+	return data, errs
+}
+
+// RegisterBatch serializes every item and hands them to Rust as a single
+// di_register_batch call, which registers all of them or none of them.
+func (c *Container) RegisterBatch(items []Registration) error {
+	if c.ptr == nil {
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	cNames := make([]*C.char, len(items))
+	cDatas := make([]*C.uint8_t, len(items))
+	cLens := make([]C.size_t, len(items))
+	for i, item := range items {
+		cNames[i] = C.CString(item.Name)
+		if len(item.Data) > 0 {
+			cDatas[i] = (*C.uint8_t)(unsafe.Pointer(&item.Data[0]))
+		}
+		cLens[i] = C.size_t(len(item.Data))
+	}
+	defer func() {
+		for _, cName := range cNames {
+			C.free(unsafe.Pointer(cName))
+		}
+	}()
+
+	clearError()
+	code := C.di_register_batch(c.ptr, &cNames[0], &cDatas[0], &cLens[0], C.size_t(len(items)))
+	if code != C.DI_OK {
+		return &DIError{Code: ErrorCode(code), Message: getLastError()}
+	}
+
+	for _, item := range items {
+		c.recordRegistration(item.Name, Singleton)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package di
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestResolveAll(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("A", 1)
+	container.RegisterValue("B", 2)
+	RegisterFactory(container, "C", Transient, func(r Resolver) (int, error) { return 3, nil })
+
+	results, err := container.ResolveAll([]string{"A", "B", "C"})
+	if err != nil {
+		t.Fatalf("ResolveAll failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if string(results["A"]) != "1" || string(results["B"]) != "2" || string(results["C"]) != "3" {
+		t.Errorf("unexpected results: %v", results)
+	}
+}
+
+func TestResolveAllPartialFailure(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("A", 1)
+
+	results, err := container.ResolveAll([]string{"A", "Missing"})
+	if err == nil {
+		t.Fatal("expected a MultiError for the missing name")
+	}
+	multi, ok := err.(*MultiError)
+	if !ok {
+		t.Fatalf("expected *MultiError, got %T", err)
+	}
+	if _, ok := multi.Errors["Missing"]; !ok {
+		t.Errorf("expected MultiError to report 'Missing', got %v", multi.Errors)
+	}
+	if string(results["A"]) != "1" {
+		t.Errorf("expected 'A' to still resolve despite 'Missing' failing, got %v", results)
+	}
+}
+
+func TestResolveAllInto(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("A", 1)
+	container.RegisterValue("B", 2)
+
+	var a, b int
+	err := container.ResolveAllInto(map[string]any{"A": &a, "B": &b})
+	if err != nil {
+		t.Fatalf("ResolveAllInto failed: %v", err)
+	}
+	if a != 1 || b != 2 {
+		t.Errorf("expected a=1 b=2, got a=%d b=%d", a, b)
+	}
+}
+
+func TestRegisterBatch(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	items := []Registration{
+		{Name: "A", Data: []byte("1")},
+		{Name: "B", Data: []byte("2")},
+	}
+	if err := container.RegisterBatch(items); err != nil {
+		t.Fatalf("RegisterBatch failed: %v", err)
+	}
+	if !container.Contains("A") || !container.Contains("B") {
+		t.Error("expected both batch registrations to succeed")
+	}
+}
+
+func BenchmarkResolveAll(b *testing.B) {
+	const n = 32
+
+	b.Run("sequential", func(b *testing.B) {
+		container := NewContainer()
+		if container == nil {
+			b.Fatal("Failed to create container")
+		}
+		defer container.Free()
+
+		names := make([]string, n)
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("Service%d", i)
+			names[i] = name
+			container.Register(name, []byte(`{"id": 1}`))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, name := range names {
+				container.Resolve(name)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		container := NewContainer()
+		if container == nil {
+			b.Fatal("Failed to create container")
+		}
+		defer container.Free()
+
+		names := make([]string, n)
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("Service%d", i)
+			names[i] = name
+			container.Register(name, []byte(`{"id": 1}`))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			container.ResolveAll(names)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package di
+
+/*
+#include "dependency_injector.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentType tags the wire format of a registration so Resolve's caller
+// can decode it with a matching Codec.
+type ContentType byte
+
+const (
+	// ContentTypeJSON is the default, human-readable wire format used by
+	// RegisterValue and every pre-codec registration path.
+	ContentTypeJSON ContentType = iota
+	// ContentTypeMsgpack trades readability for a smaller, cheaper-to-parse
+	// payload.
+	ContentTypeMsgpack
+	// ContentTypeCBOR is like ContentTypeMsgpack but self-describing, which
+	// matters when a payload needs to be inspected without its Go type.
+	ContentTypeCBOR
+)
+
+// Codec marshals and unmarshals values crossing the FFI boundary. Callers
+// that don't need JSON's readability can register a service with
+// MsgpackCodec or CBORCodec instead, trading that readability for less
+// marshaling overhead on payloads larger than a handful of fields.
+type Codec interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte, target interface{}) error
+	ContentType() ContentType
+}
+
+// JSONCodec is the default Codec. RegisterValue and ResolveTyped fall back
+// to it when no other Codec is given, preserving the behavior every
+// existing caller already depends on.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(value interface{}) ([]byte, error) { return json.Marshal(value) }
+func (JSONCodec) Unmarshal(data []byte, target interface{}) error {
+	return json.Unmarshal(data, target)
+}
+func (JSONCodec) ContentType() ContentType { return ContentTypeJSON }
+
+// MsgpackCodec marshals with MessagePack.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(value interface{}) ([]byte, error) { return msgpack.Marshal(value) }
+func (MsgpackCodec) Unmarshal(data []byte, target interface{}) error {
+	return msgpack.Unmarshal(data, target)
+}
+func (MsgpackCodec) ContentType() ContentType { return ContentTypeMsgpack }
+
+// CBORCodec marshals with CBOR.
+type CBORCodec struct{}
+
+func (CBORCodec) Marshal(value interface{}) ([]byte, error) { return cbor.Marshal(value) }
+func (CBORCodec) Unmarshal(data []byte, target interface{}) error {
+	return cbor.Unmarshal(data, target)
+}
+func (CBORCodec) ContentType() ContentType { return ContentTypeCBOR }
+
+// RegisterValueCodec is like RegisterValue but marshals value with codec
+// instead of always using JSON, tagging the registration with codec's
+// ContentType so ResolveTyped can decode it again.
+func (c *Container) RegisterValueCodec(typeName string, value interface{}, codec Codec) error {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return c.registerTyped(typeName, data, codec.ContentType())
+}
+
+// registerTyped registers data under typeName, tagging it with ct. JSON
+// keeps using the original di_register_singleton path so every existing
+// caller's behavior is unchanged; anything else goes through
+// di_register_singleton_typed so the Rust side knows how to treat the
+// bytes if it ever needs to decode them itself (e.g. for introspection).
+func (c *Container) registerTyped(typeName string, data []byte, ct ContentType) error {
+	if ct == ContentTypeJSON {
+		return c.Register(typeName, data)
+	}
+	if c.ptr == nil {
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+
+	clearError()
+	cTypeName := C.CString(typeName)
+	defer C.free(unsafe.Pointer(cTypeName))
+
+	var dataPtr *C.uint8_t
+	if len(data) > 0 {
+		dataPtr = (*C.uint8_t)(unsafe.Pointer(&data[0]))
+	}
+
+	code := C.di_register_singleton_typed(c.ptr, cTypeName, dataPtr, C.size_t(len(data)), C.uint8_t(ct))
+	if code != C.DI_OK {
+		return &DIError{Code: ErrorCode(code), Message: getLastError()}
+	}
+	c.recordRegistration(typeName, Singleton)
+	return nil
+}
+
+// ResolveTyped resolves name and decodes it into a T with codec, or
+// JSONCodec if codec is omitted, the generic counterpart to ResolveInto for
+// callers using a non-JSON wire format. Non-JSON content types are fetched
+// through resolveTypedFFI rather than Resolve/di_resolve_json, since a
+// msgpack/CBOR payload is arbitrary binary and di_resolve_json's C-string
+// return value would truncate it at its first zero byte.
+func ResolveTyped[T any](c *Container, name string, codec ...Codec) (T, error) {
+	var zero T
+	cd := Codec(JSONCodec{})
+	if len(codec) > 0 {
+		cd = codec[0]
+	}
+
+	var data []byte
+	var err error
+	if cd.ContentType() == ContentTypeJSON {
+		data, err = c.Resolve(name)
+	} else {
+		data, err = c.resolveTypedFFI(name)
+	}
+	if err != nil {
+		return zero, err
+	}
+
+	var value T
+	if err := cd.Unmarshal(data, &value); err != nil {
+		return zero, &DIError{Code: SerializationError, Message: fmt.Sprintf("resolve %s: %v", name, err)}
+	}
+	return value, nil
+}
+
+// resolveTypedFFI retrieves a service registered through registerTyped's
+// non-JSON path, returning its raw bytes exactly as registered. Unlike
+// resolveFFI/di_resolve_json, which hands back a NUL-terminated C string,
+// di_resolve_typed returns a length alongside the buffer so the bytes are
+// copied out with C.GoBytes instead of C.GoString - safe for msgpack/CBOR
+// payloads that legitimately contain a zero byte.
+func (c *Container) resolveTypedFFI(typeName string) ([]byte, error) {
+	if c.ptr == nil {
+		return nil, &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+
+	clearError()
+	cTypeName := C.CString(typeName)
+	defer C.free(unsafe.Pointer(cTypeName))
+
+	var length C.size_t
+	dataPtr := C.di_resolve_typed(c.ptr, cTypeName, &length)
+	if dataPtr == nil {
+		errMsg := getLastError()
+		if errMsg != "" {
+			return nil, &DIError{Code: NotFound, Message: errMsg}
+		}
+		return nil, &DIError{Code: NotFound, Message: fmt.Sprintf("service '%s' not found", typeName)}
+	}
+	defer C.di_buffer_free(dataPtr, length)
+
+	return C.GoBytes(unsafe.Pointer(dataPtr), C.int(length)), nil
+}
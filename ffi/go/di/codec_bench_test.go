@@ -0,0 +1,87 @@
+package di
+
+import (
+	"fmt"
+	"testing"
+)
+
+// wideCodecPayload is large enough that marshaling cost stops being noise,
+// unlike the tiny Config-sized structs most of the other benchmarks use.
+type wideCodecPayload struct {
+	ID       int
+	Name     string
+	Tags     []string
+	Metadata map[string]string
+}
+
+func makeCodecPayload(fields int) wideCodecPayload {
+	p := wideCodecPayload{
+		ID:       1,
+		Name:     "benchmark-payload",
+		Tags:     make([]string, fields),
+		Metadata: make(map[string]string, fields),
+	}
+	for i := 0; i < fields; i++ {
+		tag := fmt.Sprintf("tag-%d", i)
+		p.Tags[i] = tag
+		p.Metadata[tag] = fmt.Sprintf("value-%d", i)
+	}
+	return p
+}
+
+// BenchmarkCodecMarshal compares codec cost across payload sizes, so callers
+// can see the point at which leaving JSON's readability behind starts to
+// pay for itself.
+func BenchmarkCodecMarshal(b *testing.B) {
+	sizes := []int{1, 16, 256}
+	codecs := map[string]Codec{
+		"json":    JSONCodec{},
+		"msgpack": MsgpackCodec{},
+		"cbor":    CBORCodec{},
+	}
+
+	for _, size := range sizes {
+		payload := makeCodecPayload(size)
+		for name, codec := range codecs {
+			b.Run(fmt.Sprintf("fields=%d/%s", size, name), func(b *testing.B) {
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.Marshal(payload); err != nil {
+						b.Fatalf("Marshal failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkCodecRoundTrip is like BenchmarkCodecMarshal but includes the
+// matching Unmarshal, which is what RegisterValueCodec/ResolveTyped
+// actually pay on every call.
+func BenchmarkCodecRoundTrip(b *testing.B) {
+	sizes := []int{1, 16, 256}
+	codecs := map[string]Codec{
+		"json":    JSONCodec{},
+		"msgpack": MsgpackCodec{},
+		"cbor":    CBORCodec{},
+	}
+
+	for _, size := range sizes {
+		payload := makeCodecPayload(size)
+		for name, codec := range codecs {
+			b.Run(fmt.Sprintf("fields=%d/%s", size, name), func(b *testing.B) {
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					data, err := codec.Marshal(payload)
+					if err != nil {
+						b.Fatalf("Marshal failed: %v", err)
+					}
+					var out wideCodecPayload
+					if err := codec.Unmarshal(data, &out); err != nil {
+						b.Fatalf("Unmarshal failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package di
+
+import "testing"
+
+type codecPayload struct {
+	ID   int
+	Name string
+}
+
+func TestRegisterValueCodecMsgpack(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	want := codecPayload{ID: 1, Name: "ada"}
+	if err := container.RegisterValueCodec("Payload", want, MsgpackCodec{}); err != nil {
+		t.Fatalf("RegisterValueCodec failed: %v", err)
+	}
+
+	got, err := ResolveTyped[codecPayload](container, "Payload", MsgpackCodec{})
+	if err != nil {
+		t.Fatalf("ResolveTyped failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestRegisterValueCodecCBOR(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	want := codecPayload{ID: 2, Name: "grace"}
+	if err := container.RegisterValueCodec("Payload", want, CBORCodec{}); err != nil {
+		t.Fatalf("RegisterValueCodec failed: %v", err)
+	}
+
+	got, err := ResolveTyped[codecPayload](container, "Payload", CBORCodec{})
+	if err != nil {
+		t.Fatalf("ResolveTyped failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestRegisterValueCodecMsgpackWithZeroByte(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	// ID: 0 encodes as a literal zero byte in both msgpack and CBOR, which
+	// would truncate the payload if it were ever read back through
+	// di_resolve_json/C.GoString instead of the length-based typed path.
+	want := codecPayload{ID: 0, Name: "ada"}
+	if err := container.RegisterValueCodec("ZeroPayload", want, MsgpackCodec{}); err != nil {
+		t.Fatalf("RegisterValueCodec failed: %v", err)
+	}
+
+	got, err := ResolveTyped[codecPayload](container, "ZeroPayload", MsgpackCodec{})
+	if err != nil {
+		t.Fatalf("ResolveTyped failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestResolveTypedDefaultsToJSON(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	want := codecPayload{ID: 3, Name: "margaret"}
+	if err := container.RegisterValue("Payload", want); err != nil {
+		t.Fatalf("RegisterValue failed: %v", err)
+	}
+
+	got, err := ResolveTyped[codecPayload](container, "Payload")
+	if err != nil {
+		t.Fatalf("ResolveTyped failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,204 @@
+package di
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+)
+
+// serviceMeta is the bookkeeping kept for every registration, FFI-backed or
+// factory-backed, so Descriptors and Find can report on it without a round
+// trip through the Rust container.
+type serviceMeta struct {
+	lifetime     Lifetime
+	registeredAt time.Time
+}
+
+// ServiceDescriptor describes a single service visible from a container,
+// for debugging and introspecting a DI graph.
+type ServiceDescriptor struct {
+	// Name is the registered type name.
+	Name string
+	// Lifetime is Singleton for anything registered via Register,
+	// RegisterJSON, or RegisterValue, and whatever was passed to
+	// RegisterFactory for factory-backed registrations.
+	Lifetime Lifetime
+	// RegisteredAt is when the service was registered.
+	RegisteredAt time.Time
+	// ScopeDepth is how many Scope() hops separate the container Descriptors
+	// was called on from the container this service is registered on; 0
+	// means it's registered directly on the receiver.
+	ScopeDepth int
+	// Size is the length, in bytes, of the service's cached JSON
+	// representation, or -1 if it hasn't been materialized yet (e.g. a
+	// Transient factory, which has no stable size, or a Scoped factory that
+	// hasn't been resolved in this scope).
+	Size int
+}
+
+// Descriptors returns a ServiceDescriptor for every service visible from
+// this scope, walking parent containers. Only services registered through
+// Register, RegisterJSON, RegisterValue(Codec), RegisterBatch, RegisterNamed
+// and RegisterFactory(Func) are listed; constructors added with Provide and
+// group members added with RegisterInGroup are not.
+func (c *Container) Descriptors() []ServiceDescriptor {
+	var out []ServiceDescriptor
+	seen := make(map[string]bool)
+
+	depth := 0
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.recMu.Lock()
+		names := make([]string, 0, len(cur.meta))
+		for name := range cur.meta {
+			names = append(names, name)
+		}
+		cur.recMu.Unlock()
+		sort.Strings(names)
+
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			cur.recMu.Lock()
+			m := cur.meta[name]
+			cur.recMu.Unlock()
+
+			out = append(out, ServiceDescriptor{
+				Name:         name,
+				Lifetime:     m.lifetime,
+				RegisteredAt: m.registeredAt,
+				ScopeDepth:   depth,
+				Size:         c.cachedSize(cur, name),
+			})
+		}
+		depth++
+	}
+	return out
+}
+
+// cachedSize returns the byte length of name's materialized JSON, or -1 if
+// it has none yet. FFI-registered and Singleton/Scoped factory values are
+// always materialized once resolved; Transient factories never are, since
+// they have no single stable value to report a size for. owner is the
+// container name is registered on (which caches Singletons); c is the scope
+// Descriptors was called on (which caches its own Scoped values).
+func (c *Container) cachedSize(owner *Container, name string) int {
+	owner.mu.Lock()
+	reg, hasFactory := owner.factories[name]
+	owner.mu.Unlock()
+
+	if hasFactory {
+		switch reg.lifetime {
+		case Singleton:
+			owner.mu.Lock()
+			data, ok := owner.singletons[name]
+			owner.mu.Unlock()
+			if ok {
+				return len(data)
+			}
+		case Scoped:
+			c.mu.Lock()
+			data, ok := c.scoped[name]
+			c.mu.Unlock()
+			if ok {
+				return len(data)
+			}
+		}
+		return -1
+	}
+
+	data, err := owner.resolveFFI(name)
+	if err != nil {
+		return -1
+	}
+	return len(data)
+}
+
+// Find returns the names of every service visible from this scope whose
+// name matches pattern, using the same glob syntax as path.Match (e.g.
+// "*Service", "DB?").
+func (c *Container) Find(pattern string) []string {
+	var out []string
+	seen := make(map[string]bool)
+
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.recMu.Lock()
+		names := make([]string, 0, len(cur.meta))
+		for name := range cur.meta {
+			names = append(names, name)
+		}
+		cur.recMu.Unlock()
+
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if ok, _ := path.Match(pattern, name); ok {
+				out = append(out, name)
+			}
+		}
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// tracingResolver wraps a Container's resolution path, recording every name
+// resolved through it so DescribeGraph can render factory dependencies as
+// graph edges.
+type tracingResolver struct {
+	c     *Container
+	edges *[]string
+}
+
+func (t *tracingResolver) Resolve(typeName string) ([]byte, error) {
+	*t.edges = append(*t.edges, typeName)
+	return t.c.resolveWithStack(typeName, make(map[string]bool))
+}
+
+// DescribeGraph writes a Graphviz DOT rendering of the resolution graph
+// visible from this scope to w: one node per service, with edges to every
+// dependency its factory resolved while building it. Discovering those
+// edges means invoking each factory once against a tracing Resolver, so
+// calling DescribeGraph on a container with side-effecting factories runs
+// those side effects.
+func (c *Container) DescribeGraph(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph di {"); err != nil {
+		return err
+	}
+
+	for _, d := range c.Descriptors() {
+		if _, err := fmt.Fprintf(w, "  %q;\n", d.Name); err != nil {
+			return err
+		}
+
+		owner, reg := c.ownerAndFactory(d.Name)
+		if reg == nil {
+			continue
+		}
+		var edges []string
+		if _, err := reg.build(&tracingResolver{c: owner, edges: &edges}); err != nil {
+			continue
+		}
+		for _, dep := range edges {
+			if _, err := fmt.Fprintf(w, "  %q -> %q;\n", d.Name, dep); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// ownerAndFactory is lookupFactory with its return order flipped for
+// readability at DescribeGraph's call site.
+func (c *Container) ownerAndFactory(name string) (*Container, *factoryRegistration) {
+	reg, owner := c.lookupFactory(name)
+	return owner, reg
+}
@@ -0,0 +1,117 @@
+package di
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescriptors(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := container.RegisterValue("Config", map[string]int{"port": 8080}); err != nil {
+		t.Fatalf("Failed to register: %v", err)
+	}
+	if err := RegisterFactory(container, "Counter", Transient, func(r Resolver) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	descriptors := container.Descriptors()
+	byName := make(map[string]ServiceDescriptor, len(descriptors))
+	for _, d := range descriptors {
+		byName[d.Name] = d
+	}
+
+	config, ok := byName["Config"]
+	if !ok {
+		t.Fatal("expected Config in Descriptors()")
+	}
+	if config.Lifetime != Singleton {
+		t.Errorf("expected Config lifetime Singleton, got %v", config.Lifetime)
+	}
+	if config.Size <= 0 {
+		t.Errorf("expected Config to report a materialized size, got %d", config.Size)
+	}
+
+	counter, ok := byName["Counter"]
+	if !ok {
+		t.Fatal("expected Counter in Descriptors()")
+	}
+	if counter.Lifetime != Transient {
+		t.Errorf("expected Counter lifetime Transient, got %v", counter.Lifetime)
+	}
+	if counter.Size != -1 {
+		t.Errorf("expected Transient Counter to report unknown size, got %d", counter.Size)
+	}
+}
+
+func TestDescriptorsWalksParentScopes(t *testing.T) {
+	root := NewContainer()
+	if root == nil {
+		t.Fatal("Failed to create root container")
+	}
+	defer root.Free()
+	root.RegisterValue("RootService", 1)
+
+	child, err := root.Scope()
+	if err != nil {
+		t.Fatalf("Failed to create scope: %v", err)
+	}
+	defer child.Free()
+	child.RegisterValue("ChildService", 2)
+
+	descriptors := child.Descriptors()
+	names := make(map[string]bool, len(descriptors))
+	for _, d := range descriptors {
+		names[d.Name] = true
+	}
+	if !names["RootService"] || !names["ChildService"] {
+		t.Errorf("expected child Descriptors() to include both scopes, got %v", names)
+	}
+}
+
+func TestFind(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("UserService", 1)
+	container.RegisterValue("OrderService", 2)
+	container.RegisterValue("Config", 3)
+
+	matches := container.Find("*Service")
+	if len(matches) != 2 {
+		t.Errorf("expected 2 matches for '*Service', got %v", matches)
+	}
+}
+
+func TestDescribeGraph(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("Config", map[string]int{"port": 8080})
+	RegisterFactory(container, "Database", Singleton, func(r Resolver) (int, error) {
+		if _, err := r.Resolve("Config"); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	})
+
+	var buf strings.Builder
+	if err := container.DescribeGraph(&buf); err != nil {
+		t.Fatalf("DescribeGraph failed: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `"Database" -> "Config"`) {
+		t.Errorf("expected graph to contain Database -> Config edge, got:\n%s", out)
+	}
+}
@@ -44,10 +44,12 @@ package di
 */
 import "C"
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"runtime"
+	"sync"
+	"time"
 	"unsafe"
 )
 
@@ -67,6 +69,17 @@ const (
 	InternalError ErrorCode = 4
 	// SerializationError indicates a serialization error occurred.
 	SerializationError ErrorCode = 5
+	// CircularDependency indicates a factory-resolution cycle was detected.
+	CircularDependency ErrorCode = 6
+	// ScopeDisposed indicates an operation was attempted on a container
+	// that has already been Free'd.
+	ScopeDisposed ErrorCode = 7
+	// FactoryPanicked indicates a factory recovered from a panic instead of
+	// returning normally.
+	FactoryPanicked ErrorCode = 8
+	// Unauthorized indicates the caller isn't permitted to perform the
+	// operation.
+	Unauthorized ErrorCode = 9
 )
 
 func (e ErrorCode) Error() string {
@@ -83,6 +96,14 @@ func (e ErrorCode) Error() string {
 		return "internal error"
 	case SerializationError:
 		return "serialization error"
+	case CircularDependency:
+		return "circular dependency detected"
+	case ScopeDisposed:
+		return "container is disposed"
+	case FactoryPanicked:
+		return "factory panicked"
+	case Unauthorized:
+		return "unauthorized"
 	default:
 		return fmt.Sprintf("unknown error code: %d", e)
 	}
@@ -109,6 +130,34 @@ func (e *DIError) Is(target error) bool {
 	return false
 }
 
+// The methods below classify a DIError for the di/errdefs predicates
+// (IsNotFound, IsConflict, ...), so callers can test for a category of
+// failure with errors.As-style matching instead of switching on Code.
+
+// NotFound reports whether this error represents a missing service.
+func (e *DIError) NotFound() bool { return e.Code == NotFound }
+
+// Conflict reports whether this error represents a duplicate registration.
+func (e *DIError) Conflict() bool { return e.Code == AlreadyRegistered }
+
+// InvalidArgument reports whether this error represents a bad argument.
+func (e *DIError) InvalidArgument() bool { return e.Code == InvalidArgument }
+
+// CircularDependency reports whether this error represents a resolution
+// cycle.
+func (e *DIError) CircularDependency() bool { return e.Code == CircularDependency }
+
+// ScopeDisposed reports whether this error represents use of a Free'd
+// container.
+func (e *DIError) ScopeDisposed() bool { return e.Code == ScopeDisposed }
+
+// FactoryPanicked reports whether this error represents a recovered
+// factory panic.
+func (e *DIError) FactoryPanicked() bool { return e.Code == FactoryPanicked }
+
+// Unauthorized reports whether this error represents a rejected caller.
+func (e *DIError) Unauthorized() bool { return e.Code == Unauthorized }
+
 // getLastError retrieves the last error message from the library.
 func getLastError() string {
 	cMsg := C.di_error_message()
@@ -126,7 +175,88 @@ func clearError() {
 
 // Container wraps the Rust dependency injection container.
 type Container struct {
-	ptr *C.DiContainer
+	ptr    *C.DiContainer
+	parent *Container
+
+	mu         sync.Mutex
+	factories  map[string]*factoryRegistration
+	singletons map[string]json.RawMessage
+	scoped     map[string]json.RawMessage
+	// buildLocks serializes concurrent Resolves of the same Singleton or
+	// Scoped factory so only one of them ever actually runs the factory;
+	// everyone else blocks on the lock and then hits the now-populated
+	// cache. Created lazily per name, guarded by mu like the maps above.
+	buildLocks map[string]*sync.Mutex
+
+	// recMu guards recorder, installedModules, and meta, which Install and
+	// Descriptors mutate independently of the factory/singleton bookkeeping
+	// above.
+	recMu            sync.Mutex
+	recorder         *installRecorder
+	installedModules []string
+	meta             map[string]serviceMeta
+	order            []string
+	disposers        map[string]func(context.Context) error
+
+	// providers and goValues back Provide/Invoke: Go constructor functions
+	// and the values they produce, kept entirely on the Go side so struct
+	// and pointer singletons never pay a JSON round trip through the FFI
+	// boundary. Plain sync.Map since they're written once per type and read
+	// far more often than that.
+	providers sync.Map
+	goValues  sync.Map
+
+	// hookTimeouts and builtOrder back ProvideWithHooks/Shutdown/HealthCheck,
+	// guarded by recMu like the other registration-order bookkeeping above.
+	// They only ever hold this container's own services, so Shutdown on a
+	// Scope() never touches its parent's. hookTimeouts' keys are which
+	// services were registered through ProvideWithHooks at all; builtOrder is
+	// the order their Provide'd constructors actually ran in, which - because
+	// buildProvider always resolves a constructor's dependencies before
+	// calling it - is already a valid reverse-dependency order for teardown,
+	// unlike registration order (a dependency can be Provide'd after the
+	// thing that depends on it).
+	hookTimeouts map[string]time.Duration
+	builtOrder   []string
+
+	// named and groups back RegisterNamed/RegisterInGroup and their
+	// Resolve* counterparts, guarded by recMu. named just records which
+	// instance names exist per type, since the instances themselves are
+	// ordinary registrations under a qualified key; groups holds each
+	// member's data directly since group membership has no other home.
+	named  map[string][]string
+	groups map[string][]groupEntry
+}
+
+// recordRegistration notes that typeName was just registered with the given
+// lifetime, so Descriptors can report on it and Dispose/Free know the order
+// to tear services back down in. It also registers this registration's
+// undo with an in-flight Install, if one is active, so a later module
+// failing rolls it back.
+func (c *Container) recordRegistration(typeName string, lifetime Lifetime) {
+	c.recMu.Lock()
+	if c.meta == nil {
+		c.meta = make(map[string]serviceMeta)
+	}
+	c.meta[typeName] = serviceMeta{lifetime: lifetime, registeredAt: time.Now()}
+	c.order = append(c.order, typeName)
+	c.recMu.Unlock()
+	c.recordUndo(func() { c.unregister(typeName) })
+}
+
+// recordUndo appends undo to the in-flight Install's rollback list, if one
+// is active; it's a no-op otherwise. Every registration path that isn't
+// undone by recordRegistration's typeName-based unregister (Provide,
+// RegisterInGroup) calls this directly with its own undo instead, so
+// Install's "all or nothing" guarantee covers every registration kind, not
+// just the ones that fit the factories/singletons/meta model.
+func (c *Container) recordUndo(undo func()) {
+	c.recMu.Lock()
+	rec := c.recorder
+	c.recMu.Unlock()
+	if rec != nil {
+		rec.record(undo)
+	}
 }
 
 // NewContainer creates a new dependency injection container.
@@ -141,13 +271,18 @@ func NewContainer() *Container {
 	return c
 }
 
-// Free releases the container resources.
+// Free releases the container resources, running any disposers registered
+// via RegisterDisposable (or discovered automatically for Closer/Disposer
+// factory results) in LIFO order first. Disposal failures are logged to
+// nowhere here since Free has no error to report them through; call
+// Dispose directly if you need to observe them.
 // This is called automatically by the finalizer, but can be called explicitly.
 // Safe to call on nil container.
 func (c *Container) Free() {
 	if c == nil {
 		return
 	}
+	c.disposeAll(context.Background(), 0)
 	if c.ptr != nil {
 		C.di_container_free(c.ptr)
 		c.ptr = nil
@@ -157,7 +292,7 @@ func (c *Container) Free() {
 // Scope creates a child scope that inherits services from this container.
 func (c *Container) Scope() (*Container, error) {
 	if c.ptr == nil {
-		return nil, errors.New("container is nil or freed")
+		return nil, &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
 	}
 
 	clearError()
@@ -169,7 +304,7 @@ func (c *Container) Scope() (*Container, error) {
 		}
 	}
 
-	child := &Container{ptr: ptr}
+	child := &Container{ptr: ptr, parent: c}
 	runtime.SetFinalizer(child, (*Container).Free)
 	return child, nil
 }
@@ -177,7 +312,7 @@ func (c *Container) Scope() (*Container, error) {
 // Register registers a singleton service with the given type name and data.
 func (c *Container) Register(typeName string, data []byte) error {
 	if c.ptr == nil {
-		return errors.New("container is nil or freed")
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
 	}
 
 	clearError()
@@ -196,13 +331,14 @@ func (c *Container) Register(typeName string, data []byte) error {
 			Message: getLastError(),
 		}
 	}
+	c.recordRegistration(typeName, Singleton)
 	return nil
 }
 
 // RegisterJSON registers a singleton service with JSON data.
 func (c *Container) RegisterJSON(typeName string, jsonData string) error {
 	if c.ptr == nil {
-		return errors.New("container is nil or freed")
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
 	}
 
 	clearError()
@@ -219,23 +355,45 @@ func (c *Container) RegisterJSON(typeName string, jsonData string) error {
 			Message: getLastError(),
 		}
 	}
+	c.recordRegistration(typeName, Singleton)
 	return nil
 }
 
-// RegisterValue registers a value by serializing it to JSON.
+// RegisterValue registers a value by serializing it to JSON. Use
+// RegisterValueCodec to pick a different wire format.
 func (c *Container) RegisterValue(typeName string, value interface{}) error {
-	data, err := json.Marshal(value)
-	if err != nil {
-		return fmt.Errorf("failed to marshal value: %w", err)
-	}
-	return c.Register(typeName, data)
+	return c.RegisterValueCodec(typeName, value, JSONCodec{})
 }
 
 // Resolve retrieves a service by type name and returns its raw JSON data.
-// This uses the optimized di_resolve_json FFI function.
+// Factory-backed registrations (see RegisterFactory) are checked before
+// falling back to the optimized di_resolve_json FFI function.
 func (c *Container) Resolve(typeName string) ([]byte, error) {
+	return c.resolveWithStack(typeName, make(map[string]bool))
+}
+
+// resolveWithStack is the shared resolution path for Resolve and factory
+// Resolvers. stack tracks the names currently being built within this single
+// top-level Resolve call so that a factory depending (directly or
+// transitively) on its own name is reported as ErrCircularDependency instead
+// of recursing forever.
+func (c *Container) resolveWithStack(typeName string, stack map[string]bool) ([]byte, error) {
+	if reg, owner := c.lookupFactory(typeName); reg != nil {
+		if stack[typeName] {
+			return nil, ErrCircularDependency
+		}
+		stack[typeName] = true
+		defer delete(stack, typeName)
+		return c.resolveFactory(owner, reg, typeName, stack)
+	}
+	return c.resolveFFI(typeName)
+}
+
+// resolveFFI retrieves a service registered directly with the Rust
+// container, bypassing the Go-side factory registry.
+func (c *Container) resolveFFI(typeName string) ([]byte, error) {
 	if c.ptr == nil {
-		return nil, errors.New("container is nil or freed")
+		return nil, &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
 	}
 
 	clearError()
@@ -286,12 +444,17 @@ func (c *Container) TryResolve(typeName string) []byte {
 	return data
 }
 
-// Contains checks if a service is registered.
+// Contains checks if a service is registered, including factory-backed
+// registrations visible from this scope.
 func (c *Container) Contains(typeName string) bool {
 	if c.ptr == nil {
 		return false
 	}
 
+	if reg, _ := c.lookupFactory(typeName); reg != nil {
+		return true
+	}
+
 	cTypeName := C.CString(typeName)
 	defer C.free(unsafe.Pointer(cTypeName))
 
@@ -299,12 +462,16 @@ func (c *Container) Contains(typeName string) bool {
 	return result == 1
 }
 
-// ServiceCount returns the number of registered services.
+// ServiceCount returns the number of registered services, including
+// factory-backed registrations visible from this scope and every member of
+// every RegisterInGroup'd group (named instances are ordinary
+// registrations under a qualified key, so di_service_count already counts
+// them).
 func (c *Container) ServiceCount() int64 {
 	if c.ptr == nil {
 		return -1
 	}
-	return int64(C.di_service_count(c.ptr))
+	return int64(C.di_service_count(c.ptr)) + int64(c.factoryCount()) + int64(c.groupCount())
 }
 
 // Version returns the library version.
@@ -0,0 +1,187 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// defaultDisposeTimeout bounds a single disposer call when the caller
+// doesn't provide one (e.g. via the plain Free).
+const defaultDisposeTimeout = 5 * time.Second
+
+// Closer is satisfied by a factory result that needs a teardown step with
+// no context, such as a *sql.DB. Container.Free disposes it automatically.
+type Closer interface {
+	Close() error
+}
+
+// Disposer is satisfied by a factory result that needs a context-aware
+// teardown step, such as a gRPC client needing to drain in-flight calls.
+// Container.Free disposes it automatically.
+type Disposer interface {
+	Dispose(ctx context.Context) error
+}
+
+// DisposeError aggregates every failure encountered while disposing a
+// container's services, instead of stopping at the first.
+type DisposeError struct {
+	Errors []error
+}
+
+func (e *DisposeError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("dispose: %d error(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap lets errors.Is/As see through to the individual failures.
+func (e *DisposeError) Unwrap() []error { return e.Errors }
+
+// RegisterDisposable registers value under name, like RegisterValue, and
+// arranges for dispose to run when the container is freed.
+func (c *Container) RegisterDisposable(name string, value any, dispose func(ctx context.Context) error) error {
+	if err := c.RegisterValue(name, value); err != nil {
+		return err
+	}
+	c.setDisposer(name, dispose)
+	return nil
+}
+
+// registerAutoDisposer wires up automatic disposal for a Singleton/Scoped
+// factory result that implements Closer or Disposer, so callers don't have
+// to repeat RegisterDisposable's dispose func for every such factory.
+func (c *Container) registerAutoDisposer(name string, value any) {
+	switch v := value.(type) {
+	case Disposer:
+		c.setDisposer(name, v.Dispose)
+	case Closer:
+		c.setDisposer(name, func(context.Context) error { return v.Close() })
+	}
+}
+
+func (c *Container) setDisposer(name string, dispose func(ctx context.Context) error) {
+	c.recMu.Lock()
+	if c.disposers == nil {
+		c.disposers = make(map[string]func(context.Context) error)
+	}
+	c.disposers[name] = dispose
+	// A Scoped value built in a descendant scope is registered on an
+	// ancestor, so it isn't in this scope's order yet; add it so disposeAll
+	// still visits it.
+	known := false
+	for _, n := range c.order {
+		if n == name {
+			known = true
+			break
+		}
+	}
+	if !known {
+		c.order = append(c.order, name)
+	}
+	c.recMu.Unlock()
+}
+
+// Dispose runs every registered disposer for this container (but not its
+// ancestors) in reverse registration order, bounding each one by timeout,
+// then frees the container like Free. Unlike Free, failures are returned
+// as a *DisposeError instead of being discarded. A non-positive timeout
+// uses defaultDisposeTimeout.
+func (c *Container) Dispose(ctx context.Context, timeout time.Duration) error {
+	if c == nil {
+		return nil
+	}
+	err := c.disposeAll(ctx, timeout)
+	c.Free()
+	return err
+}
+
+// disposeAll runs every disposer registered on this container and clears
+// c.disposers before returning, so a second call - e.g. Free running after
+// Dispose already ran it - finds nothing left to dispose instead of running
+// every disposer a second time.
+func (c *Container) disposeAll(ctx context.Context, timeout time.Duration) error {
+	if c == nil {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = defaultDisposeTimeout
+	}
+
+	c.recMu.Lock()
+	order := append([]string(nil), c.order...)
+	disposers := c.disposers
+	c.disposers = nil
+	c.recMu.Unlock()
+
+	if len(disposers) == 0 {
+		return nil
+	}
+
+	var failed []error
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		dispose, ok := disposers[name]
+		if !ok {
+			continue
+		}
+		dctx, cancel := context.WithTimeout(ctx, timeout)
+		err := dispose(dctx)
+		cancel()
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return &DisposeError{Errors: failed}
+}
+
+// TrapShutdown installs a handler for sigs (SIGINT and SIGTERM if none are
+// given) that cancels the returned context and disposes root on the first
+// signal, and force-exits on the third, so a service stuck draining
+// connections can still be killed instead of hanging forever.
+func TrapShutdown(ctx context.Context, root *Container, sigs ...os.Signal) context.Context {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		var hits int32
+		for range ch {
+			switch atomic.AddInt32(&hits, 1) {
+			case 1:
+				cancel()
+				go func() {
+					if err := root.Dispose(context.Background(), 0); err != nil {
+						log.Printf("di: shutdown dispose: %v", err)
+					}
+					os.Exit(0)
+				}()
+			case 2:
+				// A second signal while disposal is still draining; keep
+				// waiting, but note it so a stuck shutdown is visible.
+				log.Printf("di: shutdown already in progress, signal again to force exit")
+			default:
+				os.Exit(1)
+			}
+		}
+	}()
+
+	return ctx
+}
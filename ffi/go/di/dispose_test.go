@@ -0,0 +1,113 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegisterDisposableRunsOnFree(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+
+	closed := false
+	err := container.RegisterDisposable("Resource", map[string]int{"id": 1}, func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register disposable: %v", err)
+	}
+
+	container.Free()
+
+	if !closed {
+		t.Error("expected disposer to run on Free")
+	}
+}
+
+func TestDisposeOrderIsLIFO(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+
+	var order []string
+	container.RegisterDisposable("First", 1, func(ctx context.Context) error {
+		order = append(order, "First")
+		return nil
+	})
+	container.RegisterDisposable("Second", 2, func(ctx context.Context) error {
+		order = append(order, "Second")
+		return nil
+	})
+
+	if err := container.Dispose(context.Background(), 0); err != nil {
+		t.Fatalf("Dispose failed: %v", err)
+	}
+
+	if len(order) != 2 || order[0] != "Second" || order[1] != "First" {
+		t.Errorf("expected LIFO disposal order [Second First], got %v", order)
+	}
+}
+
+func TestDisposeAggregatesErrors(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+
+	container.RegisterDisposable("A", 1, func(ctx context.Context) error {
+		return errors.New("a failed")
+	})
+	container.RegisterDisposable("B", 2, func(ctx context.Context) error {
+		return errors.New("b failed")
+	})
+
+	err := container.Dispose(context.Background(), 0)
+	if err == nil {
+		t.Fatal("expected Dispose to report errors")
+	}
+
+	var disposeErr *DisposeError
+	if !errors.As(err, &disposeErr) {
+		t.Fatalf("expected *DisposeError, got %T", err)
+	}
+	if len(disposeErr.Errors) != 2 {
+		t.Errorf("expected 2 aggregated errors, got %d", len(disposeErr.Errors))
+	}
+}
+
+type closerService struct{ closed *bool }
+
+func (s closerService) Close() error {
+	*s.closed = true
+	return nil
+}
+
+func TestFactoryCloserAutoDisposed(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+
+	closed := false
+	err := RegisterFactory(container, "DB", Singleton, func(r Resolver) (closerService, error) {
+		return closerService{closed: &closed}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	if _, err := container.Resolve("DB"); err != nil {
+		t.Fatalf("Failed to resolve: %v", err)
+	}
+
+	container.Free()
+
+	if !closed {
+		t.Error("expected Closer-implementing singleton to be auto-disposed on Free")
+	}
+}
@@ -0,0 +1,104 @@
+// Package errdefs defines predicates for classifying errors returned by
+// the di package without depending on its numeric ErrorCode enum.
+//
+// Each predicate walks the error's Unwrap chain looking for a type that
+// implements a small marker interface (e.g. an error implementing
+// `NotFound() bool`). di.DIError implements all of them based on its Code
+// field, and di.Wrap lets factory authors attach the same classification
+// to their own errors, so a wrapped error from a user factory still
+// classifies correctly.
+package errdefs
+
+import "errors"
+
+type notFound interface{ NotFound() bool }
+type conflict interface{ Conflict() bool }
+type invalidArgument interface{ InvalidArgument() bool }
+type circularDependency interface{ CircularDependency() bool }
+type scopeDisposed interface{ ScopeDisposed() bool }
+type factoryPanicked interface{ FactoryPanicked() bool }
+type unauthorized interface{ Unauthorized() bool }
+
+// IsNotFound reports whether err, or anything in its Unwrap chain,
+// represents a missing service.
+func IsNotFound(err error) bool {
+	for err != nil {
+		if nf, ok := err.(notFound); ok && nf.NotFound() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsConflict reports whether err, or anything in its Unwrap chain,
+// represents a duplicate registration.
+func IsConflict(err error) bool {
+	for err != nil {
+		if c, ok := err.(conflict); ok && c.Conflict() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsInvalidArgument reports whether err, or anything in its Unwrap chain,
+// represents a bad argument.
+func IsInvalidArgument(err error) bool {
+	for err != nil {
+		if ia, ok := err.(invalidArgument); ok && ia.InvalidArgument() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsCircularDependency reports whether err, or anything in its Unwrap
+// chain, represents a factory-resolution cycle.
+func IsCircularDependency(err error) bool {
+	for err != nil {
+		if cd, ok := err.(circularDependency); ok && cd.CircularDependency() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsScopeDisposed reports whether err, or anything in its Unwrap chain,
+// represents use of a Free'd container.
+func IsScopeDisposed(err error) bool {
+	for err != nil {
+		if sd, ok := err.(scopeDisposed); ok && sd.ScopeDisposed() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsFactoryPanicked reports whether err, or anything in its Unwrap chain,
+// represents a recovered factory panic.
+func IsFactoryPanicked(err error) bool {
+	for err != nil {
+		if fp, ok := err.(factoryPanicked); ok && fp.FactoryPanicked() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
+
+// IsUnauthorized reports whether err, or anything in its Unwrap chain,
+// represents a rejected caller.
+func IsUnauthorized(err error) bool {
+	for err != nil {
+		if u, ok := err.(unauthorized); ok && u.Unauthorized() {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package errdefs_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pegasusheavy/dependency-injector/ffi/go/di"
+	"github.com/pegasusheavy/dependency-injector/ffi/go/di/errdefs"
+)
+
+func TestIsNotFound(t *testing.T) {
+	container := di.NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	_, err := container.Resolve("Missing")
+	if !errdefs.IsNotFound(err) {
+		t.Errorf("expected IsNotFound to match %v", err)
+	}
+	if errdefs.IsConflict(err) {
+		t.Errorf("did not expect IsConflict to match %v", err)
+	}
+}
+
+func TestIsConflict(t *testing.T) {
+	container := di.NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterValue("Config", 1)
+	err := container.RegisterValue("Config", 2)
+	if !errdefs.IsConflict(err) {
+		t.Errorf("expected IsConflict to match %v", err)
+	}
+}
+
+func TestIsScopeDisposed(t *testing.T) {
+	container := di.NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	container.Free()
+
+	_, err := container.Resolve("Anything")
+	if !errdefs.IsScopeDisposed(err) {
+		t.Errorf("expected IsScopeDisposed to match %v", err)
+	}
+}
+
+func TestIsFactoryPanicked(t *testing.T) {
+	container := di.NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	di.RegisterFactory(container, "Boom", di.Transient, func(r di.Resolver) (int, error) {
+		panic("kaboom")
+	})
+
+	_, err := container.Resolve("Boom")
+	if !errdefs.IsFactoryPanicked(err) {
+		t.Errorf("expected IsFactoryPanicked to match %v", err)
+	}
+}
+
+func TestIsNotFoundThroughWrappedUserError(t *testing.T) {
+	wrapped := fmt.Errorf("repository lookup: %w", di.Wrap(fmt.Errorf("no rows"), di.NotFound))
+	if !errdefs.IsNotFound(wrapped) {
+		t.Errorf("expected IsNotFound to match a wrapped user error, got %v", wrapped)
+	}
+}
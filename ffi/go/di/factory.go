@@ -0,0 +1,225 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Lifetime controls how long a factory-produced instance is retained by its
+// container.
+type Lifetime int
+
+const (
+	// Transient invokes the factory on every Resolve.
+	Transient Lifetime = iota
+	// Singleton memoizes the first result in the container that owns the
+	// registration, so every descendant scope sees the same instance.
+	Singleton
+	// Scoped memoizes the first result per scope: each container returned
+	// by Scope gets its own instance, while the value is shared by anything
+	// resolving the same name from within that same scope.
+	Scoped
+)
+
+// String returns the human-readable name of the lifetime, used in error
+// messages and Descriptor output.
+func (l Lifetime) String() string {
+	switch l {
+	case Transient:
+		return "transient"
+	case Singleton:
+		return "singleton"
+	case Scoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+// Resolver is handed to factories so they can pull in their own
+// dependencies. It resolves through the same parent-walking scope chain as
+// Container.Resolve, so a factory can call r.Resolve("DatabaseService") and
+// reach a service registered on an ancestor container.
+type Resolver interface {
+	Resolve(typeName string) ([]byte, error)
+}
+
+// factoryResolver is the Resolver passed to a factory mid-resolution. It
+// resolves against the container the top-level Resolve was called on and
+// shares that call's cycle-detection stack.
+type factoryResolver struct {
+	c     *Container
+	stack map[string]bool
+}
+
+func (r *factoryResolver) Resolve(typeName string) ([]byte, error) {
+	return r.c.resolveWithStack(typeName, r.stack)
+}
+
+// factoryRegistration describes a Go-side factory registration.
+type factoryRegistration struct {
+	lifetime Lifetime
+	build    func(r Resolver) (any, error)
+}
+
+// RegisterFactoryFunc registers a non-generic factory under name. See
+// RegisterFactory for the generic, type-safe entry point.
+func (c *Container) RegisterFactoryFunc(name string, lifetime Lifetime, factory func(r Resolver) (any, error)) error {
+	if c.ptr == nil {
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.factories == nil {
+		c.factories = make(map[string]*factoryRegistration)
+	}
+	if _, exists := c.factories[name]; exists {
+		return &DIError{Code: AlreadyRegistered, Message: fmt.Sprintf("service '%s' already registered", name)}
+	}
+
+	c.factories[name] = &factoryRegistration{lifetime: lifetime, build: factory}
+	c.recordRegistration(name, lifetime)
+	return nil
+}
+
+// RegisterFactory registers a typed factory for T under name with the given
+// lifetime. Transient factories run on every Resolve; Singleton factories
+// memoize their result in the container that registered them; Scoped
+// factories memoize their result per Container.Scope(). The factory's
+// result is JSON-marshaled so it can still be retrieved through Resolve,
+// ResolveInto, and ResolveJSON like any other service.
+func RegisterFactory[T any](c *Container, name string, lifetime Lifetime, factory func(r Resolver) (T, error)) error {
+	return c.RegisterFactoryFunc(name, lifetime, func(r Resolver) (any, error) {
+		return factory(r)
+	})
+}
+
+// lookupFactory walks this container and its ancestors looking for a
+// factory registered under name, returning the registration and the
+// container that owns it (where Singleton instances are cached).
+func (c *Container) lookupFactory(name string) (*factoryRegistration, *Container) {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.Lock()
+		reg, ok := cur.factories[name]
+		cur.mu.Unlock()
+		if ok {
+			return reg, cur
+		}
+	}
+	return nil, nil
+}
+
+// factoryCount returns the number of factories registered on this container
+// and all of its ancestors.
+func (c *Container) factoryCount() int {
+	count := 0
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.Lock()
+		count += len(cur.factories)
+		cur.mu.Unlock()
+	}
+	return count
+}
+
+// resolveFactory builds (or returns the cached) value for a factory
+// registration, honoring its lifetime. c is the container Resolve was
+// called on (used for Scoped caching); owner is the container that
+// registered the factory (used for Singleton caching).
+func (c *Container) resolveFactory(owner *Container, reg *factoryRegistration, name string, stack map[string]bool) ([]byte, error) {
+	switch reg.lifetime {
+	case Singleton:
+		return buildCachedOnce(owner, &owner.singletons, reg, c, name, stack)
+
+	case Scoped:
+		return buildCachedOnce(c, &c.scoped, reg, c, name, stack)
+
+	default: // Transient
+		_, data, err := buildFactory(reg, c, stack)
+		return data, err
+	}
+}
+
+// buildCachedOnce makes the Singleton/Scoped check-then-build sequence
+// atomic: the cache is checked without the lock for the common already-built
+// case, then again under cacheOwner's per-name build lock before actually
+// invoking the factory, so two concurrent Resolves of the same name can't
+// both miss the cache and both run the factory (and both register an
+// auto-disposer, leaking the first instance's). cacheOwner is the container
+// whose map backs the cache (owner for Singleton, c for Scoped).
+func buildCachedOnce(cacheOwner *Container, cache *map[string]json.RawMessage, reg *factoryRegistration, c *Container, name string, stack map[string]bool) ([]byte, error) {
+	cacheOwner.mu.Lock()
+	if cached, ok := (*cache)[name]; ok {
+		cacheOwner.mu.Unlock()
+		return cached, nil
+	}
+	cacheOwner.mu.Unlock()
+
+	lock := cacheOwner.buildLock(name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cacheOwner.mu.Lock()
+	if cached, ok := (*cache)[name]; ok {
+		cacheOwner.mu.Unlock()
+		return cached, nil
+	}
+	cacheOwner.mu.Unlock()
+
+	value, data, err := buildFactory(reg, c, stack)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheOwner.mu.Lock()
+	if *cache == nil {
+		*cache = make(map[string]json.RawMessage)
+	}
+	(*cache)[name] = data
+	cacheOwner.mu.Unlock()
+	cacheOwner.registerAutoDisposer(name, value)
+	return data, nil
+}
+
+// buildLock returns the mutex that serializes builds of name on this
+// container, creating it on first use.
+func (c *Container) buildLock(name string) *sync.Mutex {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.buildLocks == nil {
+		c.buildLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := c.buildLocks[name]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.buildLocks[name] = lock
+	}
+	return lock
+}
+
+// buildFactory invokes the factory and marshals its result to JSON,
+// returning the unmarshaled value too so Singleton/Scoped callers can check
+// it for Closer/Disposer before it's discarded. A panicking factory is
+// recovered and reported as a FactoryPanicked error instead of taking down
+// the caller.
+func buildFactory(reg *factoryRegistration, c *Container, stack map[string]bool) (value any, data []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			value, data = nil, nil
+			err = &DIError{Code: FactoryPanicked, Message: fmt.Sprintf("%v", r)}
+		}
+	}()
+
+	value, err = reg.build(&factoryResolver{c: c, stack: stack})
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err = json.Marshal(value)
+	return value, data, err
+}
+
+// ErrCircularDependency is returned when resolving a service requires,
+// directly or transitively, resolving itself again.
+var ErrCircularDependency = &DIError{Code: CircularDependency}
@@ -0,0 +1,196 @@
+package di
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type factoryConfig struct {
+	Port int `json:"port"`
+}
+
+func TestRegisterFactoryTransient(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	calls := 0
+	err := RegisterFactory(container, "Counter", Transient, func(r Resolver) (int, error) {
+		calls++
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		var got int
+		if err := container.ResolveJSON("Counter", &got); err != nil {
+			t.Fatalf("Failed to resolve: %v", err)
+		}
+		if got != i {
+			t.Errorf("call %d: expected %d, got %d", i, i, got)
+		}
+	}
+}
+
+func TestRegisterFactorySingleton(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	calls := 0
+	err := RegisterFactory(container, "Config", Singleton, func(r Resolver) (factoryConfig, error) {
+		calls++
+		return factoryConfig{Port: 8080}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		var got factoryConfig
+		if err := container.ResolveJSON("Config", &got); err != nil {
+			t.Fatalf("Failed to resolve: %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected singleton factory to run once, ran %d times", calls)
+	}
+}
+
+func TestRegisterFactoryScoped(t *testing.T) {
+	root := NewContainer()
+	if root == nil {
+		t.Fatal("Failed to create root container")
+	}
+	defer root.Free()
+
+	calls := 0
+	err := RegisterFactory(root, "RequestID", Scoped, func(r Resolver) (int, error) {
+		calls++
+		return calls, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	scopeA, err := root.Scope()
+	if err != nil {
+		t.Fatalf("Failed to create scope: %v", err)
+	}
+	defer scopeA.Free()
+
+	scopeB, err := root.Scope()
+	if err != nil {
+		t.Fatalf("Failed to create scope: %v", err)
+	}
+	defer scopeB.Free()
+
+	var a1, a2, b1 int
+	if err := scopeA.ResolveJSON("RequestID", &a1); err != nil {
+		t.Fatalf("Failed to resolve in scopeA: %v", err)
+	}
+	if err := scopeA.ResolveJSON("RequestID", &a2); err != nil {
+		t.Fatalf("Failed to resolve in scopeA: %v", err)
+	}
+	if err := scopeB.ResolveJSON("RequestID", &b1); err != nil {
+		t.Fatalf("Failed to resolve in scopeB: %v", err)
+	}
+
+	if a1 != a2 {
+		t.Errorf("expected scoped factory to memoize within scopeA, got %d then %d", a1, a2)
+	}
+	if a1 == b1 {
+		t.Errorf("expected scopeA and scopeB to get independent instances, both got %d", a1)
+	}
+	if calls != 2 {
+		t.Errorf("expected factory to run once per scope (2 total), ran %d times", calls)
+	}
+}
+
+func TestRegisterFactoryCircularDependency(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	RegisterFactory(container, "A", Transient, func(r Resolver) (int, error) {
+		_, err := r.Resolve("B")
+		return 0, err
+	})
+	RegisterFactory(container, "B", Transient, func(r Resolver) (int, error) {
+		_, err := r.Resolve("A")
+		return 0, err
+	})
+
+	_, err := container.Resolve("A")
+	if err == nil {
+		t.Fatal("Expected circular dependency error")
+	}
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Errorf("Expected ErrCircularDependency, got %v", err)
+	}
+}
+
+func TestRegisterFactoryResolverDelegatesToParent(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := container.RegisterValue("DatabaseService", factoryConfig{Port: 5432}); err != nil {
+		t.Fatalf("Failed to register database: %v", err)
+	}
+
+	err := RegisterFactory(container, "Repo", Transient, func(r Resolver) (factoryConfig, error) {
+		var db factoryConfig
+		data, err := r.Resolve("DatabaseService")
+		if err != nil {
+			return db, err
+		}
+		if err := json.Unmarshal(data, &db); err != nil {
+			return db, err
+		}
+		return db, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register factory: %v", err)
+	}
+
+	var repo factoryConfig
+	if err := container.ResolveJSON("Repo", &repo); err != nil {
+		t.Fatalf("Failed to resolve repo: %v", err)
+	}
+	if repo.Port != 5432 {
+		t.Errorf("expected factory to see DatabaseService via Resolver, got %+v", repo)
+	}
+}
+
+func TestRegisterFactoryAlreadyRegistered(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := RegisterFactory(container, "Config", Singleton, func(r Resolver) (int, error) { return 1, nil }); err != nil {
+		t.Fatalf("First registration should succeed: %v", err)
+	}
+
+	err := RegisterFactory(container, "Config", Singleton, func(r Resolver) (int, error) { return 2, nil })
+	if err == nil {
+		t.Fatal("Second registration should fail")
+	}
+	if !errors.Is(err, ErrAlreadyRegistered) {
+		t.Errorf("Expected ErrAlreadyRegistered, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// typeNameOf derives the name Register/Provide/Resolve use for T, so the
+// caller never has to spell out a string key themselves.
+func typeNameOf[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// Register registers value under a name derived from T, so it can later be
+// retrieved with Resolve[T]. It's the generic counterpart to
+// Container.RegisterValue for callers who'd rather not manage string keys.
+func Register[T any](c *Container, value T) error {
+	return c.RegisterValue(typeNameOf[T](), value)
+}
+
+// Provide registers ctor as the constructor for T under a name derived from
+// T, modeled on samber/do's Provide. Unlike Container.Provide, ctor
+// receives the container itself (matching do.Injector's role) rather than
+// having its parameters auto-wired by reflection.
+func Provide[T any](c *Container, ctor func(*Container) (T, error)) error {
+	name := typeNameOf[T]()
+	return c.RegisterFactoryFunc(name, Singleton, func(r Resolver) (any, error) {
+		return ctor(c)
+	})
+}
+
+// Resolve resolves the service registered under a name derived from T and
+// unmarshals it into a T, so callers get a fully typed result without
+// writing out ResolveInto themselves.
+func Resolve[T any](c *Container) (T, error) {
+	var zero T
+	data, err := c.Resolve(typeNameOf[T]())
+	if err != nil {
+		return zero, err
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return zero, &DIError{Code: SerializationError, Message: fmt.Sprintf("resolve %s: %v", typeNameOf[T](), err)}
+	}
+	return value, nil
+}
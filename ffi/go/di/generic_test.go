@@ -0,0 +1,62 @@
+package di
+
+import "testing"
+
+type genericUser struct {
+	Name string
+}
+
+func TestGenericRegisterResolve(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := Register(container, genericUser{Name: "ada"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	got, err := Resolve[genericUser](container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got.Name != "ada" {
+		t.Errorf("expected Name=ada, got %+v", got)
+	}
+}
+
+func TestGenericProvideResolvePointer(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	err := Provide(container, func(c *Container) (*genericUser, error) {
+		return &genericUser{Name: "grace"}, nil
+	})
+	if err != nil {
+		t.Fatalf("Provide failed: %v", err)
+	}
+
+	got, err := Resolve[*genericUser](container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got == nil || got.Name != "grace" {
+		t.Errorf("expected *genericUser{Name: grace}, got %+v", got)
+	}
+}
+
+func TestGenericResolveMissing(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if _, err := Resolve[genericUser](container); err == nil {
+		t.Fatal("expected Resolve to fail for an unregistered type")
+	}
+}
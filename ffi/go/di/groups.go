@@ -0,0 +1,128 @@
+package di
+
+// namedKey builds the qualified name RegisterNamed/ResolveNamed register
+// under, so a named instance behaves exactly like any other registration
+// once it's been resolved - the qualification is only visible to
+// RegisterNamed and ResolveNamed themselves.
+func namedKey(typeName, instanceName string) string {
+	return typeName + "#" + instanceName
+}
+
+// RegisterNamed registers data as a named instance of typeName, for cases
+// like a "primary" vs a "replica" *sql.DB where both share a Go type but
+// need to be resolved independently.
+func (c *Container) RegisterNamed(typeName, instanceName string, data []byte) error {
+	if err := c.Register(namedKey(typeName, instanceName), data); err != nil {
+		return err
+	}
+
+	c.recMu.Lock()
+	if c.named == nil {
+		c.named = make(map[string][]string)
+	}
+	c.named[typeName] = append(c.named[typeName], instanceName)
+	idx := len(c.named[typeName]) - 1
+	c.recMu.Unlock()
+
+	c.recordUndo(func() {
+		c.recMu.Lock()
+		defer c.recMu.Unlock()
+		names := c.named[typeName]
+		if idx < len(names) {
+			c.named[typeName] = append(names[:idx], names[idx+1:]...)
+		}
+	})
+	return nil
+}
+
+// ResolveNamed resolves the named instance of typeName registered by
+// RegisterNamed.
+func (c *Container) ResolveNamed(typeName, instanceName string) ([]byte, error) {
+	return c.Resolve(namedKey(typeName, instanceName))
+}
+
+// NamedInstances returns the instance names registered for typeName via
+// RegisterNamed, walking this container's ancestors like Resolve does: this
+// container's names come first, in registration order, followed by each
+// ancestor's, nearest first.
+func (c *Container) NamedInstances(typeName string) []string {
+	var names []string
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.recMu.Lock()
+		names = append(names, cur.named[typeName]...)
+		cur.recMu.Unlock()
+	}
+	return names
+}
+
+// groupEntry is one member of a RegisterInGroup'd group, in registration
+// order.
+type groupEntry struct {
+	typeName string
+	data     []byte
+}
+
+// RegisterInGroup appends data, registered under typeName, as a member of
+// groupName. Unlike RegisterNamed, a group member isn't individually
+// addressable - it's meant to be retrieved as part of the whole group with
+// ResolveGroup, e.g. every HTTPMiddleware implementation in the order it
+// was registered.
+func (c *Container) RegisterInGroup(groupName, typeName string, data []byte) error {
+	c.recMu.Lock()
+	if c.groups == nil {
+		c.groups = make(map[string][]groupEntry)
+	}
+	c.groups[groupName] = append(c.groups[groupName], groupEntry{typeName: typeName, data: data})
+	idx := len(c.groups[groupName]) - 1
+	c.recMu.Unlock()
+
+	c.recordUndo(func() {
+		c.recMu.Lock()
+		defer c.recMu.Unlock()
+		entries := c.groups[groupName]
+		if idx < len(entries) {
+			c.groups[groupName] = append(entries[:idx], entries[idx+1:]...)
+		}
+	})
+	return nil
+}
+
+// ResolveGroup returns the data for every member of groupName in
+// registration order, walking from the furthest ancestor down to this
+// container so a group assembled across Scope() boundaries still comes
+// back in the order its members were added. Group membership is tracked
+// entirely on the Go side as a plain slice, so this is O(group size)
+// regardless of how many other services the container holds - see
+// BenchmarkGroupResolution for the comparison against resolving each
+// member by name.
+func (c *Container) ResolveGroup(groupName string) [][]byte {
+	var chain []*Container
+	for cur := c; cur != nil; cur = cur.parent {
+		chain = append(chain, cur)
+	}
+
+	var results [][]byte
+	for i := len(chain) - 1; i >= 0; i-- {
+		cur := chain[i]
+		cur.recMu.Lock()
+		for _, entry := range cur.groups[groupName] {
+			results = append(results, entry.data)
+		}
+		cur.recMu.Unlock()
+	}
+	return results
+}
+
+// groupCount returns the total number of group members registered on this
+// container and all of its ancestors, for ServiceCount.
+func (c *Container) groupCount() int {
+	count := 0
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.recMu.Lock()
+		for _, entries := range cur.groups {
+			count += len(entries)
+		}
+		cur.recMu.Unlock()
+	}
+	return count
+}
@@ -0,0 +1,143 @@
+package di
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRegisterNamedAndResolveNamed(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterNamed("Database", "primary", []byte(`{"role": "primary"}`))
+	container.RegisterNamed("Database", "replica", []byte(`{"role": "replica"}`))
+
+	primary, err := container.ResolveNamed("Database", "primary")
+	if err != nil {
+		t.Fatalf("ResolveNamed(primary) failed: %v", err)
+	}
+	replica, err := container.ResolveNamed("Database", "replica")
+	if err != nil {
+		t.Fatalf("ResolveNamed(replica) failed: %v", err)
+	}
+	if string(primary) == string(replica) {
+		t.Errorf("expected primary and replica to resolve independently, got %s and %s", primary, replica)
+	}
+
+	names := container.NamedInstances("Database")
+	if len(names) != 2 || names[0] != "primary" || names[1] != "replica" {
+		t.Errorf("expected [primary replica], got %v", names)
+	}
+}
+
+func TestRegisterInGroupAndResolveGroup(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	container.RegisterInGroup("HTTPMiddleware", "LoggingMiddleware", []byte(`"logging"`))
+	container.RegisterInGroup("HTTPMiddleware", "AuthMiddleware", []byte(`"auth"`))
+	container.RegisterInGroup("HTTPMiddleware", "CORSMiddleware", []byte(`"cors"`))
+
+	members := container.ResolveGroup("HTTPMiddleware")
+	if len(members) != 3 {
+		t.Fatalf("expected 3 group members, got %d", len(members))
+	}
+	want := []string{`"logging"`, `"auth"`, `"cors"`}
+	for i, data := range members {
+		if string(data) != want[i] {
+			t.Errorf("member %d: expected %s, got %s", i, want[i], data)
+		}
+	}
+}
+
+func TestResolveGroupWalksAncestors(t *testing.T) {
+	parent := NewContainer()
+	if parent == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer parent.Free()
+
+	parent.RegisterInGroup("HTTPMiddleware", "LoggingMiddleware", []byte(`"logging"`))
+
+	child, err := parent.Scope()
+	if err != nil {
+		t.Fatalf("Scope failed: %v", err)
+	}
+	child.RegisterInGroup("HTTPMiddleware", "AuthMiddleware", []byte(`"auth"`))
+
+	members := child.ResolveGroup("HTTPMiddleware")
+	if len(members) != 2 {
+		t.Fatalf("expected 2 group members across scopes, got %d", len(members))
+	}
+	if string(members[0]) != `"logging"` || string(members[1]) != `"auth"` {
+		t.Errorf("expected ancestor members first, got %v", members)
+	}
+}
+
+func TestServiceCountIncludesGroupMembers(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	before := container.ServiceCount()
+	container.RegisterInGroup("HTTPMiddleware", "LoggingMiddleware", []byte(`"logging"`))
+	container.RegisterInGroup("HTTPMiddleware", "AuthMiddleware", []byte(`"auth"`))
+
+	after := container.ServiceCount()
+	if after != before+2 {
+		t.Errorf("expected ServiceCount to grow by 2, went from %d to %d", before, after)
+	}
+}
+
+func BenchmarkGroupResolution(b *testing.B) {
+	const n = 64
+
+	b.Run("group", func(b *testing.B) {
+		container := NewContainer()
+		if container == nil {
+			b.Fatal("Failed to create container")
+		}
+		defer container.Free()
+
+		for i := 0; i < n; i++ {
+			container.RegisterInGroup("HTTPMiddleware", fmt.Sprintf("Middleware%d", i), []byte(fmt.Sprintf("%d", i)))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			container.ResolveGroup("HTTPMiddleware")
+		}
+	})
+
+	b.Run("per_name_lookup", func(b *testing.B) {
+		container := NewContainer()
+		if container == nil {
+			b.Fatal("Failed to create container")
+		}
+		defer container.Free()
+
+		names := make([]string, n)
+		for i := 0; i < n; i++ {
+			name := fmt.Sprintf("Middleware%d", i)
+			names[i] = name
+			container.Register(name, []byte(fmt.Sprintf("%d", i)))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			members := make([][]byte, 0, n)
+			for _, name := range names {
+				data, _ := container.Resolve(name)
+				members = append(members, data)
+			}
+		}
+	})
+}
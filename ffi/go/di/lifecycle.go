@@ -0,0 +1,157 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// defaultShutdownTimeout bounds a single service's Shutdown call when its
+// ProvideWithHooks registration didn't override it with WithShutdownTimeout.
+const defaultShutdownTimeout = 5 * time.Second
+
+// Shutdowner is implemented by a ProvideWithHooks'd service that needs a
+// graceful teardown step, such as draining an in-flight gRPC client or
+// closing a DB pool, when its container shuts down.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// HealthChecker is implemented by a ProvideWithHooks'd service that can
+// report its own liveness, so Container.HealthCheck can fan out across
+// every such service instead of the caller wiring up ad hoc checks.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// ServiceOption configures a single ProvideWithHooks registration.
+type ServiceOption func(*serviceHookConfig)
+
+type serviceHookConfig struct {
+	shutdownTimeout time.Duration
+}
+
+// WithShutdownTimeout overrides defaultShutdownTimeout for one service.
+func WithShutdownTimeout(d time.Duration) ServiceOption {
+	return func(cfg *serviceHookConfig) { cfg.shutdownTimeout = d }
+}
+
+// ProvideWithHooks is like Provide, but additionally tracks ctor's result
+// for Container.Shutdown and Container.HealthCheck if it implements
+// Shutdowner and/or HealthChecker respectively. Services are shut down in
+// the reverse of the order they were actually built in, which - since a
+// constructor's dependencies are always built before the constructor
+// itself - means a service is always torn down before the things it
+// depends on, regardless of the order they were ProvideWithHooks'd in.
+func (c *Container) ProvideWithHooks(ctor any, opts ...ServiceOption) error {
+	t := reflect.TypeOf(ctor)
+	if t == nil || t.Kind() != reflect.Func || t.NumOut() < 1 || t.NumOut() > 2 {
+		return &DIError{Code: InvalidArgument, Message: "ProvideWithHooks requires a constructor function returning (T) or (T, error)"}
+	}
+
+	cfg := serviceHookConfig{shutdownTimeout: defaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	name := typeKey(t.Out(0))
+	if err := c.Provide(ctor); err != nil {
+		return err
+	}
+
+	c.recMu.Lock()
+	if c.hookTimeouts == nil {
+		c.hookTimeouts = make(map[string]time.Duration)
+	}
+	c.hookTimeouts[name] = cfg.shutdownTimeout
+	c.recMu.Unlock()
+	c.recordUndo(func() {
+		c.recMu.Lock()
+		delete(c.hookTimeouts, name)
+		c.recMu.Unlock()
+	})
+	return nil
+}
+
+// Shutdown walks this container's own built services in reverse dependency
+// order - the reverse of builtOrder, not of registration - calling Shutdown
+// on every one that was ProvideWithHooks'd and implements Shutdowner, and
+// aggregating their errors with errors.Join. Services on a parent scope are
+// left alone; call Shutdown on that scope separately. Services that were
+// registered but never actually resolved are skipped - there's nothing to
+// tear down.
+func (c *Container) Shutdown(ctx context.Context) error {
+	c.recMu.Lock()
+	order := append([]string(nil), c.builtOrder...)
+	timeouts := c.hookTimeouts
+	c.recMu.Unlock()
+
+	var errs []error
+	for i := len(order) - 1; i >= 0; i-- {
+		name := order[i]
+		timeout, hooked := timeouts[name]
+		if !hooked {
+			continue
+		}
+		value, ok := c.goValues.Load(name)
+		if !ok {
+			continue
+		}
+		s, ok := value.(Shutdowner)
+		if !ok {
+			continue
+		}
+
+		if timeout <= 0 {
+			timeout = defaultShutdownTimeout
+		}
+		sctx, cancel := context.WithTimeout(ctx, timeout)
+		err := s.Shutdown(sctx)
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HealthCheck calls HealthCheck concurrently on every one of this
+// container's own built, ProvideWithHooks'd services that implements
+// HealthChecker, returning one error (nil on success) per service name.
+func (c *Container) HealthCheck(ctx context.Context) map[string]error {
+	c.recMu.Lock()
+	order := append([]string(nil), c.builtOrder...)
+	timeouts := c.hookTimeouts
+	c.recMu.Unlock()
+
+	results := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range order {
+		if _, hooked := timeouts[name]; !hooked {
+			continue
+		}
+		value, ok := c.goValues.Load(name)
+		if !ok {
+			continue
+		}
+		hc, ok := value.(HealthChecker)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, hc HealthChecker) {
+			defer wg.Done()
+			err := hc.HealthCheck(ctx)
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name, hc)
+	}
+	wg.Wait()
+	return results
+}
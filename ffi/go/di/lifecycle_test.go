@@ -0,0 +1,201 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type lifecycleDB struct {
+	shutdown bool
+	healthy  bool
+}
+
+func (db *lifecycleDB) Shutdown(ctx context.Context) error {
+	db.shutdown = true
+	return nil
+}
+
+func (db *lifecycleDB) HealthCheck(ctx context.Context) error {
+	if !db.healthy {
+		return errors.New("not ready")
+	}
+	return nil
+}
+
+func TestShutdownCallsShutdowner(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	db := &lifecycleDB{healthy: true}
+	if err := container.ProvideWithHooks(func() (*lifecycleDB, error) { return db, nil }); err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+	if err := container.Invoke(func(*lifecycleDB) error { return nil }); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	if err := container.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if !db.shutdown {
+		t.Error("expected Shutdown to be called on the service")
+	}
+}
+
+func TestShutdownSkipsUnresolvedServices(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	db := &lifecycleDB{}
+	if err := container.ProvideWithHooks(func() (*lifecycleDB, error) { return db, nil }); err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+
+	if err := container.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if db.shutdown {
+		t.Error("expected Shutdown not to touch a service that was never resolved")
+	}
+}
+
+type failingShutdowner struct{ label string }
+
+func (f *failingShutdowner) Shutdown(ctx context.Context) error {
+	return errors.New(f.label + " failed")
+}
+
+func TestShutdownAggregatesErrors(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	first := &failingShutdowner{label: "first"}
+	if err := container.ProvideWithHooks(func() (*failingShutdowner, error) { return first, nil }); err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+	if err := container.Invoke(func(*failingShutdowner) error { return nil }); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	err := container.Shutdown(context.Background())
+	if err == nil {
+		t.Fatal("expected Shutdown to aggregate the service's error")
+	}
+}
+
+func TestHealthCheckFansOut(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	db := &lifecycleDB{healthy: false}
+	container.ProvideWithHooks(func() (*lifecycleDB, error) { return db, nil })
+	if err := container.Invoke(func(*lifecycleDB) error { return nil }); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	results := container.HealthCheck(context.Background())
+	name := typeKey(reflect.TypeOf(db))
+	if err := results[name]; err == nil {
+		t.Error("expected HealthCheck to report the unhealthy service")
+	}
+}
+
+type lifecycleUpstream struct{ shutdownOrder *[]string }
+
+func (u *lifecycleUpstream) Shutdown(ctx context.Context) error {
+	*u.shutdownOrder = append(*u.shutdownOrder, "upstream")
+	return nil
+}
+
+type lifecycleDownstream struct {
+	shutdownOrder *[]string
+	upstream      *lifecycleUpstream
+}
+
+func (d *lifecycleDownstream) Shutdown(ctx context.Context) error {
+	*d.shutdownOrder = append(*d.shutdownOrder, "downstream")
+	return nil
+}
+
+func TestShutdownOrderFollowsDependenciesNotRegistration(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	var order []string
+	// Registered before its own dependency, so reverse-registration order
+	// would shut it down after upstream instead of before.
+	if err := container.ProvideWithHooks(func(u *lifecycleUpstream) (*lifecycleDownstream, error) {
+		return &lifecycleDownstream{shutdownOrder: &order, upstream: u}, nil
+	}); err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+	if err := container.ProvideWithHooks(func() (*lifecycleUpstream, error) {
+		return &lifecycleUpstream{shutdownOrder: &order}, nil
+	}); err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+
+	if err := container.Invoke(func(*lifecycleDownstream) error { return nil }); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+
+	if err := container.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	want := []string{"downstream", "upstream"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("expected shutdown order %v, got %v", want, order)
+	}
+}
+
+type deadlineShutdowner struct{ remaining *time.Duration }
+
+func (d *deadlineShutdowner) Shutdown(ctx context.Context) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		*d.remaining = time.Until(deadline)
+	}
+	return nil
+}
+
+func TestProvideWithHooksRespectsCustomTimeout(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	var remaining time.Duration
+	err := container.ProvideWithHooks(func() (*deadlineShutdowner, error) {
+		return &deadlineShutdowner{remaining: &remaining}, nil
+	}, WithShutdownTimeout(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("ProvideWithHooks failed: %v", err)
+	}
+	if err := container.Invoke(func(*deadlineShutdowner) error { return nil }); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if err := container.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if remaining <= 0 || remaining > 10*time.Millisecond {
+		t.Errorf("expected Shutdown's context deadline to be at most 10ms away, got %v", remaining)
+	}
+}
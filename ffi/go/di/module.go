@@ -0,0 +1,137 @@
+package di
+
+/*
+#include "dependency_injector.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// Module describes a group of related registrations that can be applied to
+// a container as a single unit, so libraries can ship a reusable
+// "database + cache + logger" bundle instead of callers hand-registering
+// each service in main.
+type Module struct {
+	// Name identifies the module, e.g. in InstalledModules() output and
+	// Install error messages.
+	Name string
+	// Provide performs the module's registrations against c.
+	Provide func(c *Container) error
+}
+
+// Combine merges mods into a single Module that applies each of them, in
+// order, when installed.
+func Combine(mods ...Module) Module {
+	names := make([]string, len(mods))
+	for i, m := range mods {
+		names[i] = m.Name
+	}
+	return Module{
+		Name: strings.Join(names, "+"),
+		Provide: func(c *Container) error {
+			for _, m := range mods {
+				if err := m.Provide(c); err != nil {
+					return fmt.Errorf("module %q: %w", m.Name, err)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// installRecorder tracks the undo closures for every registration made
+// during a single Install call, regardless of what kind of registration it
+// was, so they can be run in reverse if a later module fails.
+type installRecorder struct {
+	mu   sync.Mutex
+	undo []func()
+}
+
+func (r *installRecorder) record(undo func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.undo = append(r.undo, undo)
+}
+
+// Install applies mods to the container atomically: either every module's
+// registrations succeed, or none of them do. If module N fails, every
+// registration made by modules 0..N (including the ones module N itself
+// managed to make before failing) is rolled back before Install returns,
+// so a failed Install leaves the container exactly as it was.
+func (c *Container) Install(mods ...Module) error {
+	if c.ptr == nil {
+		return &DIError{Code: ScopeDisposed, Message: "container is nil or freed"}
+	}
+
+	rec := &installRecorder{}
+	c.recMu.Lock()
+	prev := c.recorder
+	c.recorder = rec
+	c.recMu.Unlock()
+	defer func() {
+		c.recMu.Lock()
+		c.recorder = prev
+		c.recMu.Unlock()
+	}()
+
+	applied := make([]string, 0, len(mods))
+	for _, m := range mods {
+		if err := m.Provide(c); err != nil {
+			for i := len(rec.undo) - 1; i >= 0; i-- {
+				rec.undo[i]()
+			}
+			return fmt.Errorf("module %q: %w", m.Name, err)
+		}
+		applied = append(applied, m.Name)
+	}
+
+	c.recMu.Lock()
+	c.installedModules = append(c.installedModules, applied...)
+	c.recMu.Unlock()
+	return nil
+}
+
+// InstalledModules returns the names of modules successfully installed on
+// this container, in installation order.
+func (c *Container) InstalledModules() []string {
+	c.recMu.Lock()
+	defer c.recMu.Unlock()
+	out := make([]string, len(c.installedModules))
+	copy(out, c.installedModules)
+	return out
+}
+
+// unregister removes a previously registered service, both the Go-side
+// factory/cache state and the Rust-side entry, as part of rolling back a
+// failed Install. It is best-effort: callers only reach it for names this
+// same Install call just added, so by construction the name exists.
+func (c *Container) unregister(name string) {
+	c.mu.Lock()
+	delete(c.factories, name)
+	delete(c.singletons, name)
+	delete(c.scoped, name)
+	c.mu.Unlock()
+
+	c.recMu.Lock()
+	delete(c.meta, name)
+	delete(c.disposers, name)
+	for i, n := range c.order {
+		if n == name {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.recMu.Unlock()
+
+	if c.ptr == nil {
+		return
+	}
+	cTypeName := C.CString(name)
+	defer C.free(unsafe.Pointer(cTypeName))
+	C.di_unregister(c.ptr, cTypeName)
+}
@@ -0,0 +1,149 @@
+package di
+
+import "testing"
+
+func TestInstallAppliesAllModules(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	dbModule := Module{
+		Name: "database",
+		Provide: func(c *Container) error {
+			return c.RegisterValue("DatabaseService", map[string]int{"pool_size": 10})
+		},
+	}
+	cacheModule := Module{
+		Name: "cache",
+		Provide: func(c *Container) error {
+			return c.RegisterValue("CacheService", map[string]bool{"enabled": true})
+		},
+	}
+
+	if err := container.Install(dbModule, cacheModule); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+
+	if !container.Contains("DatabaseService") || !container.Contains("CacheService") {
+		t.Error("expected both modules' services to be registered")
+	}
+
+	got := container.InstalledModules()
+	if len(got) != 2 || got[0] != "database" || got[1] != "cache" {
+		t.Errorf("unexpected InstalledModules: %v", got)
+	}
+}
+
+func TestInstallRollsBackOnFailure(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	goodModule := Module{
+		Name: "good",
+		Provide: func(c *Container) error {
+			return c.RegisterValue("ServiceA", map[string]int{"id": 1})
+		},
+	}
+	badModule := Module{
+		Name: "bad",
+		Provide: func(c *Container) error {
+			if err := c.RegisterValue("ServiceB", map[string]int{"id": 2}); err != nil {
+				return err
+			}
+			// Duplicate registration within the same module forces failure
+			// after ServiceB has already been registered.
+			return c.RegisterValue("ServiceB", map[string]int{"id": 3})
+		},
+	}
+
+	err := container.Install(goodModule, badModule)
+	if err == nil {
+		t.Fatal("expected Install to fail")
+	}
+
+	if container.Contains("ServiceA") || container.Contains("ServiceB") {
+		t.Error("expected Install to roll back all registrations from this call on failure")
+	}
+	if len(container.InstalledModules()) != 0 {
+		t.Error("expected no modules to be recorded as installed after rollback")
+	}
+}
+
+type moduleRollbackService struct{}
+
+func TestInstallRollsBackProvideAndGroups(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	goodModule := Module{
+		Name: "good",
+		Provide: func(c *Container) error {
+			if err := c.Provide(func() (*moduleRollbackService, error) {
+				return &moduleRollbackService{}, nil
+			}); err != nil {
+				return err
+			}
+			if err := c.RegisterInGroup("HTTPMiddleware", "LoggingMiddleware", []byte(`"logging"`)); err != nil {
+				return err
+			}
+			return c.RegisterNamed("DatabaseService", "primary", []byte(`"primary-db"`))
+		},
+	}
+	badModule := Module{
+		Name: "bad",
+		Provide: func(c *Container) error {
+			return c.RegisterValue("ServiceB", map[string]int{"id": 2})
+		},
+	}
+	// Force failure by installing "bad" twice so the second Install call
+	// sees ServiceB already registered and the whole call rolls back.
+	if err := container.Install(badModule); err != nil {
+		t.Fatalf("first Install failed: %v", err)
+	}
+
+	err := container.Install(goodModule, badModule)
+	if err == nil {
+		t.Fatal("expected the second Install to fail")
+	}
+
+	if err := container.Invoke(func(*moduleRollbackService) error { return nil }); err == nil {
+		t.Error("expected the rolled-back Provide constructor to no longer be registered")
+	}
+	if members := container.ResolveGroup("HTTPMiddleware"); len(members) != 0 {
+		t.Errorf("expected the rolled-back group registration to be undone, got %v", members)
+	}
+	if names := container.NamedInstances("DatabaseService"); len(names) != 0 {
+		t.Errorf("expected the rolled-back named instance to be undone, got %v", names)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	a := Module{Name: "a", Provide: func(c *Container) error { return c.RegisterValue("A", 1) }}
+	b := Module{Name: "b", Provide: func(c *Container) error { return c.RegisterValue("B", 2) }}
+
+	combined := Combine(a, b)
+	if combined.Name != "a+b" {
+		t.Errorf("expected combined name 'a+b', got %q", combined.Name)
+	}
+
+	if err := container.Install(combined); err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if !container.Contains("A") || !container.Contains("B") {
+		t.Error("expected both combined modules' services to be registered")
+	}
+}
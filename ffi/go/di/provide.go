@@ -0,0 +1,212 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ctorProvider wraps a constructor registered through Provide. value is
+// built at most once, guarded by mu/done, and cached for every later
+// lookup. mu is its own mutex rather than the container's recMu because
+// building a value can recurse into resolving other providers.
+type ctorProvider struct {
+	ctor reflect.Value
+
+	mu    sync.Mutex
+	done  bool
+	value any
+	err   error
+}
+
+// typeKey derives the name a constructor or parameter is resolved under.
+// Using reflect.Type.String() means a constructor returning *Database and a
+// parameter of type *Database always agree on the name, without requiring
+// the caller to pick one themselves the way RegisterFactory does.
+func typeKey(t reflect.Type) string {
+	return t.String()
+}
+
+// Provide registers ctor, a function of the form func(deps...) (T, error)
+// or func(deps...) T, as the constructor for T. Unlike RegisterFactory,
+// dependencies are discovered by inspecting ctor's parameter types with
+// reflect rather than named explicitly, and the constructed value is kept
+// on the Go side instead of being marshaled to JSON, so Provide is the
+// right tool for wiring together ordinary Go structs and interfaces. Use
+// Invoke to call a function whose parameters should be resolved the same
+// way. T is always treated as a singleton: ctor runs at most once per
+// container.
+func (c *Container) Provide(ctor any) error {
+	ctorVal := reflect.ValueOf(ctor)
+	t := ctorVal.Type()
+	if t.Kind() != reflect.Func {
+		return &DIError{Code: InvalidArgument, Message: "Provide requires a constructor function"}
+	}
+	if t.NumOut() < 1 || t.NumOut() > 2 {
+		return &DIError{Code: InvalidArgument, Message: "constructor must return (T) or (T, error)"}
+	}
+	if t.NumOut() == 2 && !t.Out(1).Implements(errorType) {
+		return &DIError{Code: InvalidArgument, Message: "constructor's second return value must be error"}
+	}
+
+	name := typeKey(t.Out(0))
+	if _, loaded := c.providers.LoadOrStore(name, &ctorProvider{ctor: ctorVal}); loaded {
+		return &DIError{Code: AlreadyRegistered, Message: fmt.Sprintf("constructor for %s already registered", name)}
+	}
+	c.recordUndo(func() {
+		c.providers.Delete(name)
+		c.goValues.Delete(name)
+	})
+	return nil
+}
+
+// Invoke calls fn, resolving each of its parameters the same way Provide
+// resolves a constructor's dependencies: first against Go-side values
+// built by Provide, then by falling back to Resolve and unmarshaling the
+// JSON result for types the FFI container already knows about. Any error
+// value among fn's results is returned; a panic inside fn is recovered and
+// reported as a FactoryPanicked error.
+func (c *Container) Invoke(fn any) error {
+	fnVal := reflect.ValueOf(fn)
+	t := fnVal.Type()
+	if t.Kind() != reflect.Func {
+		return &DIError{Code: InvalidArgument, Message: "Invoke requires a function"}
+	}
+
+	stack := make(map[string]bool)
+	args := make([]reflect.Value, t.NumIn())
+	for i := range args {
+		v, err := c.resolveGoType(t.In(i), stack)
+		if err != nil {
+			return err
+		}
+		args[i] = v
+	}
+
+	_, err := callFunc(fnVal, args)
+	return err
+}
+
+// resolveGoType resolves the value a Provide'd constructor or Invoke'd
+// function parameter of type t should receive, walking this container and
+// its ancestors first for an already-built value, then for a registered
+// constructor, falling back to the FFI-backed Resolve for anything else.
+// stack guards against a constructor depending, directly or indirectly, on
+// its own type.
+func (c *Container) resolveGoType(t reflect.Type, stack map[string]bool) (reflect.Value, error) {
+	name := typeKey(t)
+	if stack[name] {
+		return reflect.Value{}, ErrCircularDependency
+	}
+
+	for cur := c; cur != nil; cur = cur.parent {
+		if v, ok := cur.goValues.Load(name); ok {
+			return reflect.ValueOf(v), nil
+		}
+	}
+
+	for cur := c; cur != nil; cur = cur.parent {
+		raw, ok := cur.providers.Load(name)
+		if !ok {
+			continue
+		}
+		stack[name] = true
+		v, err := cur.buildProvider(raw.(*ctorProvider), name, stack)
+		delete(stack, name)
+		return v, err
+	}
+
+	return c.jsonFallback(t, name)
+}
+
+// buildProvider constructs p's value on first use and caches it in
+// owner.goValues for every later resolution, including ones on descendant
+// scopes. Building always resolves p's own dependencies first (the
+// resolveGoType calls below), so the order values are actually built in -
+// recorded into owner.builtOrder - is already in dependency order, with
+// every dependency appearing before the things built from it.
+func (owner *Container) buildProvider(p *ctorProvider, name string, stack map[string]bool) (reflect.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.done {
+		ctorType := p.ctor.Type()
+		args := make([]reflect.Value, ctorType.NumIn())
+		var buildErr error
+		for i := range args {
+			v, err := owner.resolveGoType(ctorType.In(i), stack)
+			if err != nil {
+				buildErr = err
+				break
+			}
+			args[i] = v
+		}
+
+		if buildErr != nil {
+			p.err = buildErr
+		} else if value, err := callFunc(p.ctor, args); err != nil {
+			p.err = err
+		} else {
+			p.value = value
+			owner.goValues.Store(name, value)
+			owner.recMu.Lock()
+			owner.builtOrder = append(owner.builtOrder, name)
+			owner.recMu.Unlock()
+		}
+		p.done = true
+	}
+
+	if p.err != nil {
+		return reflect.Value{}, p.err
+	}
+	return reflect.ValueOf(p.value), nil
+}
+
+// jsonFallback resolves name through the regular FFI/factory-backed
+// Resolve path and unmarshals it into a value of type t, for parameters
+// that aren't produced by any Provide'd constructor.
+func (c *Container) jsonFallback(t reflect.Type, name string) (reflect.Value, error) {
+	data, err := c.Resolve(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	target := t
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	v := reflect.New(target)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	if t.Kind() == reflect.Ptr {
+		return v, nil
+	}
+	return v.Elem(), nil
+}
+
+// callFunc calls fn with args and returns its first result (or nil if fn
+// has none), recovering a panic into a FactoryPanicked DIError the way
+// buildFactory does for RegisterFactory.
+func callFunc(fn reflect.Value, args []reflect.Value) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &DIError{Code: FactoryPanicked, Message: fmt.Sprintf("%v", r)}
+		}
+	}()
+
+	out := fn.Call(args)
+	for _, o := range out {
+		if o.Type().Implements(errorType) {
+			if !o.IsNil() {
+				return nil, o.Interface().(error)
+			}
+			continue
+		}
+		result = o.Interface()
+	}
+	return result, nil
+}
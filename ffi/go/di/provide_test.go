@@ -0,0 +1,130 @@
+package di
+
+import (
+	"errors"
+	"testing"
+)
+
+type providedConfig struct {
+	DSN string
+}
+
+type providedDatabase struct {
+	cfg *providedConfig
+}
+
+func TestProvideAndInvoke(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := container.Provide(func() (*providedConfig, error) {
+		return &providedConfig{DSN: "postgres://localhost"}, nil
+	}); err != nil {
+		t.Fatalf("Provide(config) failed: %v", err)
+	}
+
+	if err := container.Provide(func(cfg *providedConfig) (*providedDatabase, error) {
+		return &providedDatabase{cfg: cfg}, nil
+	}); err != nil {
+		t.Fatalf("Provide(database) failed: %v", err)
+	}
+
+	var got *providedDatabase
+	err := container.Invoke(func(db *providedDatabase) error {
+		got = db
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if got == nil || got.cfg == nil || got.cfg.DSN != "postgres://localhost" {
+		t.Errorf("unexpected invoke result: %+v", got)
+	}
+}
+
+func TestProvideMemoizesSingleton(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	calls := 0
+	container.Provide(func() (*providedConfig, error) {
+		calls++
+		return &providedConfig{DSN: "once"}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := container.Invoke(func(cfg *providedConfig) error { return nil }); err != nil {
+			t.Fatalf("Invoke failed: %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected constructor to run once, ran %d times", calls)
+	}
+}
+
+func TestProvideAlreadyRegistered(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	ctor := func() (*providedConfig, error) { return &providedConfig{}, nil }
+	if err := container.Provide(ctor); err != nil {
+		t.Fatalf("first Provide failed: %v", err)
+	}
+	if err := container.Provide(ctor); err == nil {
+		t.Fatal("expected second Provide of the same type to fail")
+	}
+}
+
+func TestProvideRejectsNonFunction(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	if err := container.Provide(42); err == nil {
+		t.Fatal("expected Provide to reject a non-function value")
+	}
+}
+
+func TestInvokePropagatesError(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	want := errors.New("boom")
+	err := container.Invoke(func() error { return want })
+	if !errors.Is(err, want) {
+		t.Errorf("expected Invoke to return the function's error, got %v", err)
+	}
+}
+
+func TestProvideCircularDependency(t *testing.T) {
+	container := NewContainer()
+	if container == nil {
+		t.Fatal("Failed to create container")
+	}
+	defer container.Free()
+
+	type a struct{}
+	type b struct{}
+
+	container.Provide(func(*b) (*a, error) { return &a{}, nil })
+	container.Provide(func(*a) (*b, error) { return &b{}, nil })
+
+	err := container.Invoke(func(*a) error { return nil })
+	if err == nil {
+		t.Fatal("expected circular dependency between Provide'd constructors to be reported")
+	}
+}
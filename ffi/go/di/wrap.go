@@ -0,0 +1,35 @@
+package di
+
+import "fmt"
+
+// wrappedError attaches a di ErrorCode classification to an arbitrary
+// error, so factory authors can return their own errors while still
+// classifying cleanly under di/errdefs.
+type wrappedError struct {
+	err  error
+	code ErrorCode
+}
+
+// Wrap attaches code's classification to err for factory authors: the
+// result satisfies the relevant di/errdefs predicate (IsNotFound,
+// IsConflict, ...) for code while still unwrapping to err.
+func Wrap(err error, code ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+	return &wrappedError{err: err, code: code}
+}
+
+func (w *wrappedError) Error() string {
+	return fmt.Sprintf("%s: %v", w.code.Error(), w.err)
+}
+
+func (w *wrappedError) Unwrap() error { return w.err }
+
+func (w *wrappedError) NotFound() bool           { return w.code == NotFound }
+func (w *wrappedError) Conflict() bool           { return w.code == AlreadyRegistered }
+func (w *wrappedError) InvalidArgument() bool    { return w.code == InvalidArgument }
+func (w *wrappedError) CircularDependency() bool { return w.code == CircularDependency }
+func (w *wrappedError) ScopeDisposed() bool      { return w.code == ScopeDisposed }
+func (w *wrappedError) FactoryPanicked() bool    { return w.code == FactoryPanicked }
+func (w *wrappedError) Unauthorized() bool       { return w.code == Unauthorized }